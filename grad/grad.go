@@ -0,0 +1,111 @@
+/*
+Package grad implements gradient based optimizers, which act on functions of
+one dimensional slices of float64.
+
+The functions to be minimized take a []float64, and return a float64. Their
+gradients, which take a []float64 and return a []float64 of the same length,
+may be passed in directly, or be left as nil, in which case they are
+approximated numerically using central finite differences.
+
+All errors encountered in this package, such as passing a learning rate
+which is not positive, are treated as critical error, and thus, the code
+immediately panics. In such cases, the function in which the error was
+encountered is printed to the screen along with the reason for the panic,
+in addition to the full stack trace, in order to help fix any issues
+rapidly.
+
+As mentioned, all the functions in this library act on Go primitive types,
+which allows the code to be easily modified to serve in different situations.
+*/
+package grad
+
+import (
+	"fmt"
+	"math"
+)
+
+var (
+	errStrings = []string{
+		"\ngocrunch/grad error.\nIn grad.%s, cannot use %s on an empty []float64.\n",
+		"\ngocrunch/grad error.\nIn grad.%s, the learning rate must be greater than 0.0, but received %f.\n",
+		"\ngocrunch/grad error.\nIn grad.%s, the number of iterations cannot be negative, but received %d.\n",
+		"\ngocrunch/grad error.\nIn grad.%s, the gradient has length %d, but the position has length %d.\n",
+	}
+)
+
+/*
+GradientDescent minimizes a function using plain gradient descent, starting
+from the passed position x0. In each iteration, the position is moved against
+the gradient, scaled by the learning rate lr:
+
+	x = x - lr * grad(x)
+
+for the passed number of iterations. The minimum value of f found, and the
+position at which it was found are returned, in that order. For example:
+
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + x[1]*x[1]
+	}
+	min, pos := grad.GradientDescent(f, nil, []float64{3.0, -4.0}, 0.1, 100)
+
+If the passed gradient is nil, as above, then it is approximated using
+central finite differences, which costs two evaluations of f per dimension
+in every iteration.
+
+The passed x0 cannot be empty, the learning rate must be greater than 0.0,
+and the number of iterations cannot be negative. The passed x0 is not
+mutated in this function.
+*/
+func GradientDescent(f func([]float64) float64, grad func([]float64) []float64, x0 []float64, lr float64, iters int) (float64, []float64) {
+	if len(x0) == 0 {
+		panic(fmt.Sprintf(errStrings[0], "GradientDescent()", "GradientDescent()"))
+	}
+	if !(lr > 0.0) {
+		panic(fmt.Sprintf(errStrings[1], "GradientDescent()", lr))
+	}
+	if iters < 0 {
+		panic(fmt.Sprintf(errStrings[2], "GradientDescent()", iters))
+	}
+	if grad == nil {
+		grad = func(x []float64) []float64 {
+			return centralDiff(f, x)
+		}
+	}
+	x := make([]float64, len(x0))
+	copy(x, x0)
+	best := f(x)
+	bestX := make([]float64, len(x))
+	copy(bestX, x)
+	for i := 0; i < iters; i++ {
+		g := grad(x)
+		if len(g) != len(x) {
+			panic(fmt.Sprintf(errStrings[3], "GradientDescent()", len(g), len(x)))
+		}
+		for j := range x {
+			x[j] -= lr * g[j]
+		}
+		if val := f(x); val < best {
+			best = val
+			copy(bestX, x)
+		}
+	}
+	return best, bestX
+}
+
+// centralDiff approximates the gradient of f at x using central finite
+// differences, with a step scaled to the magnitude of each coordinate.
+func centralDiff(f func([]float64) float64, x []float64) []float64 {
+	g := make([]float64, len(x))
+	xh := make([]float64, len(x))
+	copy(xh, x)
+	for i := range x {
+		h := 1e-6 * math.Max(1.0, math.Abs(x[i]))
+		xh[i] = x[i] + h
+		fp := f(xh)
+		xh[i] = x[i] - h
+		fm := f(xh)
+		xh[i] = x[i]
+		g[i] = (fp - fm) / (2.0 * h)
+	}
+	return g
+}
@@ -0,0 +1,46 @@
+package grad
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestGradientDescent(t *testing.T) {
+	f := func(x []float64) float64 {
+		return (x[0]-1.0)*(x[0]-1.0) + (x[1]+2.0)*(x[1]+2.0)
+	}
+	df := func(x []float64) []float64 {
+		return []float64{2.0 * (x[0] - 1.0), 2.0 * (x[1] + 2.0)}
+	}
+	x0 := []float64{3.0, 4.0}
+	min, pos := GradientDescent(f, df, x0, 0.1, 200)
+	if min > 1e-12 {
+		t.Errorf("expected a minimum of 0.0, got %e", min)
+	}
+	if math.Abs(pos[0]-1.0) > 1e-6 || math.Abs(pos[1]+2.0) > 1e-6 {
+		t.Errorf("expected the minimum at [1.0, -2.0], got %v", pos)
+	}
+	if x0[0] != 3.0 || x0[1] != 4.0 {
+		t.Errorf("expected x0 to be intact, got %v", x0)
+	}
+	min, pos = GradientDescent(f, nil, x0, 0.1, 200)
+	if math.Abs(pos[0]-1.0) > 1e-6 || math.Abs(pos[1]+2.0) > 1e-6 {
+		t.Errorf("with a numerical gradient, expected [1.0, -2.0], got %v", pos)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[1], "GradientDescent()", 0.0)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		GradientDescent(f, df, x0, 0.0, 10)
+	}()
+	wg.Wait()
+}
@@ -14,12 +14,21 @@ for wide range of situations. This trades compile time safety for runtime errors
 We believe that Go's fast compile time, along with the verbose errors in this
 package make up for that, however.
 
+Functions which act on a [][]float64 always take it as their first argument,
+followed by any indices, axes, or options. For example, column 2 of m is
+mat.Col(m, 2), the sum of row 1 is mat.Sum(m, 0, 1), and applying f to each
+element is mat.Foreach(m, f). The only exceptions are the BLAS style
+mat.Axpy and mat.Gemm, which follow the argument order of their BLAS
+namesakes, and mat.Map, mat.Apply2, and mat.ForeachParallel, which take the
+function first, as in the older numgo package.
+
 All errors encountered in this package, such as attempting to access an
 element out of bounds are treated as critical error, and thus, the code
 immediately panics. In such cases, the function in which the error was
 encountered is printed to the screen along with the reason for the panic,
 in addition to the full stack trace, in order to help fix any issues
-rapidly.
+rapidly. When the panics are not wanted, for example in a server where the
+inputs come from users, wrap the calls in mat.Try to get an error instead.
 
 As mentioned, all the functions in this library act on Go primitive types,
 which allows the code to be easily modified to serve in different situations.
@@ -27,13 +36,20 @@ which allows the code to be easily modified to serve in different situations.
 package mat
 
 import (
+	"bytes"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"os"
+	"runtime"
 	"runtime/debug"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 /*
@@ -50,55 +66,59 @@ which return a x by x (square) [][]float64. Alternatively
 
 	m := mat.New(x, y)
 
-is a [][]float64 with x rows and y columns.
-
+is a [][]float64 with x rows and y columns. New panics if any of the
+dimensions are not greater than 0; use mat.NewSafe to get an error instead.
 */
 func New(dims ...int) [][]float64 {
-	var m [][]float64
+	var r, c int
 	switch len(dims) {
 	case 1:
-		r := dims[0]
-		if r <= 0 {
-			fmt.Println("\ngocrunch/mat error.")
-			s := "In mat.%s, the number of rows must be greater than '0', but\n"
-			s += "received %d. "
-			s = fmt.Sprintf(s, "New()", r)
-			panic(s)
-		}
-		m = make([][]float64, r)
-		for i := range m {
-			m[i] = make([]float64, r)
-		}
+		r, c = dims[0], dims[0]
 	case 2:
-		r := dims[0]
-		c := dims[1]
-		if r <= 0 {
-			fmt.Println("\ngocrunch/mat error.")
-			s := "In mat.%s, the number of rows must be greater than '0', but\n"
-			s += "received %d. "
-			s = fmt.Sprintf(s, "New()", r)
-			panic(s)
-		}
-		if c <= 0 {
-			fmt.Println("\ngocrunch/mat error.")
-			s := "In mat.%s, the number of columns must be greater than '0', but\n"
-			s += "received %d. "
-			s = fmt.Sprintf(s, "New()", c)
-			panic(s)
-		}
-		m = make([][]float64, r)
-		for i := range m {
-			m[i] = make([]float64, c)
-		}
+		r, c = dims[0], dims[1]
 	default:
 		fmt.Println("\ngocrunch/mat error.")
 		s := "In mat.%s expected 1 or 2 arguments, but recieved %d"
 		s = fmt.Sprintf(s, "New()", len(dims))
 		panic(s)
 	}
+	m, err := NewSafe(r, c)
+	if err != nil {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, %v.\n"
+		s = fmt.Sprintf(s, "New()", err)
+		panic(s)
+	}
 	return m
 }
 
+/*
+NewSafe returns a [][]float64 with r rows and c columns, where all elements
+are 0.0, just like mat.New(r, c). However, instead of panicking when either
+dimension is not greater than 0, it returns a nil [][]float64 and an error.
+This is useful when the dimensions come from user input:
+
+	m, err := mat.NewSafe(rows, cols)
+	if err != nil {
+		return err
+	}
+*/
+func NewSafe(r, c int) ([][]float64, error) {
+	if r <= 0 {
+		s := "the number of rows must be greater than 0, but received %d"
+		return nil, fmt.Errorf(s, r)
+	}
+	if c <= 0 {
+		s := "the number of columns must be greater than 0, but received %d"
+		return nil, fmt.Errorf(s, c)
+	}
+	m := make([][]float64, r)
+	for i := range m {
+		m[i] = make([]float64, c)
+	}
+	return m, nil
+}
+
 /*
 I returns a square [][]float64 with all elements alone the diagonal equal to
 1.0, and 0.0 elsewhere. This is the identity matrix.
@@ -135,6 +155,9 @@ func FromCSV(filename string) [][]float64 {
 	}
 	defer f.Close()
 	r := csv.NewReader(f)
+	// The number of entries in each line is checked below, which gives a
+	// clearer message than the csv package's own check.
+	r.FieldsPerRecord = -1
 	// I am going with the assumption that a [][]float64 loaded from a CSV is going to
 	// be large. So, we are going to read one line, and determine the number
 	// of columns based on the number of comma separated strings in that line.
@@ -177,7 +200,7 @@ func FromCSV(filename string) [][]float64 {
 		if len(str) != len(row) {
 			fmt.Println("\ngocrunch/mat error.")
 			s := "In mat.%v, line %d in %s has %d entries. The first line\n"
-			s += "(line 0) has %d entries.\n"
+			s += "(line 1) has %d entries.\n"
 			s += "All lines must have the same number of comma separated entries."
 			s = fmt.Sprintf(s, "FromCSV()", line, filename, len(str), len(row))
 			panic(s)
@@ -201,7 +224,9 @@ For 1 argument, such as
 
 	mat.Rand(x, y, arg)
 
-the range is [0, arg) for arg > 0, or (arg, 0] if arg < 0.
+the range is [0, arg) for arg > 0, or (arg, 0] if arg < 0. In both cases
+the values are rand.Float64() * arg, so 0.0 can be produced but arg cannot.
+If arg is 0.0, all of the elements are 0.0.
 
 For 2 arguments, such as
 
@@ -264,6 +289,49 @@ func Flatten(m [][]float64) []float64 {
 	return n
 }
 
+/*
+CSVShape returns the number of rows and columns of a CSV (comma separated
+values) file, without converting any of its entries to float64. For example:
+
+	rows, cols, err := mat.CSVShape("data.csv")
+	if err != nil {
+		// reject the file...
+	}
+	m := mat.New(rows, cols)
+
+The number of columns is taken from the first line, and an error is returned
+if any other line has a different number of entries, or if the file cannot
+be opened or parsed. An empty file has 0 rows and 0 columns. Like
+mat.FromCSV, the file is read one line at a time.
+*/
+func CSVShape(filename string) (int, int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	rows, cols := 0, 0
+	for {
+		str, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		rows++
+		if rows == 1 {
+			cols = len(str)
+		} else if len(str) != cols {
+			s := "line %d in %s has %d entries, but the first line (line 1) has %d entries"
+			return 0, 0, fmt.Errorf(s, rows, filename, len(str), cols)
+		}
+	}
+	return rows, cols, nil
+}
+
 /*
 ToCSV writes the content of a passed [][]float64 into a CSV file with the passed
 name, by putting each row in a single comma separated line. The number of
@@ -299,6 +367,76 @@ func ToCSV(m [][]float64, fileName string) error {
 	return nil
 }
 
+/*
+AppendCSV writes the content of a passed [][]float64 to the end of a CSV file
+with the passed name, using the same format as mat.ToCSV. This is useful for
+collecting results as they are produced:
+
+	for step := 0; step < steps; step++ {
+		// compute the next rows, m...
+		if err := mat.AppendCSV(m, "results.csv"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+If the file does not exist it is created. If it already has content, a
+newline is written before the new rows unless the file already ends with
+one, and the number of entries in each new row must equal the number of
+entries in the first line of the file. An error is returned if they differ,
+in which case nothing is written. Any errors found during opening, reading,
+and writing to the file are also returned, or nil if no errors were seen.
+The passed [][]float64 is not mutated in this function.
+*/
+func AppendCSV(m [][]float64, filename string) error {
+	f, err := os.OpenFile(filename, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	cols := len(m[0])
+	var buf bytes.Buffer
+	if info.Size() > 0 {
+		first, err := csv.NewReader(f).Read()
+		if err != nil {
+			return err
+		}
+		cols = len(first)
+		last := make([]byte, 1)
+		if _, err = f.ReadAt(last, info.Size()-1); err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			buf.WriteString("\n")
+		}
+	}
+	for i := range m {
+		if len(m[i]) != cols {
+			s := "row %d has %d entries, but the lines of %s must have %d entries"
+			return fmt.Errorf(s, i, len(m[i]), filename, cols)
+		}
+	}
+	for i := range m {
+		for j := range m[i] {
+			buf.WriteString(strconv.FormatFloat(m[i][j], 'e', 14, 64))
+			if j+1 != cols {
+				buf.WriteString(",")
+			}
+		}
+		if i+1 != len(m) {
+			buf.WriteString("\n")
+		}
+	}
+	_, err = f.Write(buf.Bytes())
+	return err
+}
+
 /*
 Foreach applies a given function to each element of a [][]float64. The resultant
 [][]float64 is returned, leaving the orginal [][]float64 intact.
@@ -786,6 +924,41 @@ func T(m [][]float64) [][]float64 {
 	return n
 }
 
+/*
+TJagged returns the transpose of a [][]float64 which may be jagged, meaning
+that its rows may have different lengths. Short rows are treated as if they
+were padded on the right with the passed fill value, so that the result is
+rectangular. For example:
+
+	m := [][]float64{{1.0, 2.0, 3.0}, {4.0}}
+	n := mat.TJagged(m, 0.0) // n is [[1.0, 4.0], [2.0, 0.0], [3.0, 0.0]]
+
+The result has one row for each entry of the longest row of m, and one
+column for each row of m. An empty [][]float64, or one where all rows are
+empty, gives an empty [][]float64. For non-jagged input this is the same as
+mat.T(m). The original [][]float64 is not mutated in this function.
+*/
+func TJagged(m [][]float64, fill float64) [][]float64 {
+	cols := 0
+	for i := range m {
+		if len(m[i]) > cols {
+			cols = len(m[i])
+		}
+	}
+	n := make([][]float64, cols)
+	for j := range n {
+		n[j] = make([]float64, len(m))
+		for i := range m {
+			if j < len(m[i]) {
+				n[j][i] = m[i][j]
+			} else {
+				n[j][i] = fill
+			}
+		}
+	}
+	return n
+}
+
 /*
 All checks if a supplied function is true for all elements of a mat object.
 The supplied function is expected to have the signature of a function that
@@ -849,8 +1022,7 @@ func Any(m [][]float64, f func(float64) bool) bool {
 /*
 Sum returns the sum of all elements in a [][]float64. For example:
 
-	m := mat.New(10, 5)
-	mat.Set(m, 1.0)
+	m := mat.Set(mat.New(10, 5), 1.0)
 	x := mat.Sum(m) // x is 50.0
 
 It is also possible for this function to return the sum of a specific row
@@ -935,11 +1107,10 @@ func Sum(m [][]float64, args ...int) float64 {
 /*
 Prod returns the product of all elements in a [][]float64. For example:
 
-	m := mat.New(2, 2)
-	mat.Set(m, 2.0)
+	m := mat.Set(mat.New(2, 2), 2.0)
 	x := mat.Prod(m) // x is 16.0
 
-It is also possible for this function to return the Product of a specific row
+It is also possible for this function to return the product of a specific row
 or column in a [][]float64, by passing two additional integers to it: The
 first integer must be either 0 for picking a row, or 1 for picking a column.
 The second integer determines the specific row or column for which the product is
@@ -1022,11 +1193,10 @@ func Prod(m [][]float64, args ...int) float64 {
 Avg returns the average value of all the elements in a [][]float64. For
 example:
 
-	m := mat.New(12, 13)
-	mat.Set(m, 1.0)
+	m := mat.Set(mat.New(12, 13), 1.0)
 	x := mat.Avg(m) // x is 1.0
 
-It's also possible to return the sum of a specific row or column in
+It's also possible to return the average of a specific row or column in
 a [][]float64, by passing two additional integers to it: The first integer
 must be either 0 for picking a row, or 1 for picking a column. The second
 integer determines the specific row or column for which the average is desired.
@@ -1035,7 +1205,7 @@ last row of a [][]float64 is given by:
 
 	mat.Avg(m, 0, -1)
 
-where as the sum of the first column is given by:
+where as the average of the first column is given by:
 
 	mat.Avg(m, 1, 0)
 
@@ -1059,7 +1229,7 @@ func Avg(m [][]float64, args ...int) float64 {
 			x := args[1]
 			if (x >= len(m)) || (x < -len(m)) {
 				fmt.Println("\ngocrunch/mat error.")
-				s := "In mat.%s the requested column %d is outside of bounds [-%d, %d)\n"
+				s := "In mat.%s the requested row %d is outside of bounds [-%d, %d)\n"
 				s = fmt.Sprintf(s, "Avg()", x, len(m), len(m))
 				panic(s)
 			}
@@ -1079,7 +1249,7 @@ func Avg(m [][]float64, args ...int) float64 {
 			if (x >= len(m[0])) || (x < -len(m[0])) {
 				fmt.Println("\ngocrunch/mat error.")
 				s := "In mat.%s the requested column %d is outside of bounds [-%d, %d)\n"
-				s = fmt.Sprintf(s, "AvgCol()", x, len(m[0]), len(m[0]))
+				s = fmt.Sprintf(s, "Avg()", x, len(m[0]), len(m[0]))
 				panic(s)
 			}
 			if x >= 0 {
@@ -1155,9 +1325,9 @@ The passed arguments are not mutated by this function.
 func AppendCol(m [][]float64, v []float64) [][]float64 {
 	if len(v) != len(m) {
 		fmt.Println("\ngocrunch/mat error.")
-		s := "The length of the first argument is %d,\n"
+		s := "In mat.%s, the number of rows of the first argument is %d,\n"
 		s += "while the len of the second argument is %d. They must match.\n"
-		s += fmt.Sprintf(s, "AppendCol()", len(m), len(v))
+		s = fmt.Sprintf(s, "AppendCol()", len(m), len(v))
 		debug.PrintStack()
 		panic(s)
 	}
@@ -1167,3 +1337,2985 @@ func AppendCol(m [][]float64, v []float64) [][]float64 {
 	}
 	return n
 }
+
+/*
+AppendRow returns a copy of a passed [][]float64, with the second argument, a
+[]float64, appended as a new row at its bottom. For example, consider:
+
+	m := mat.New(2, 2) // [[0.0, 0.0], [0.0, 0.0]]
+	v := []float64{1.0, 2.0}
+	n := mat.AppendRow(m, v) // [[0.0, 0.0], [0.0, 0.0], [1.0, 2.0]]
+
+The length of the []float64 must equal the number of columns of the
+[][]float64. If the [][]float64 is empty, the result has v as its only row,
+so rows can be built up one at a time starting from nil. The passed
+arguments are not mutated by this function, and the new row is a copy of v.
+*/
+func AppendRow(m [][]float64, v []float64) [][]float64 {
+	row := make([]float64, len(v))
+	copy(row, v)
+	if len(m) == 0 {
+		return [][]float64{row}
+	}
+	if len(v) != len(m[0]) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of columns of the first argument is %d,\n"
+		s += "while the len of the second argument is %d. They must match.\n"
+		s = fmt.Sprintf(s, "AppendRow()", len(m[0]), len(v))
+		panic(s)
+	}
+	n := Clone(m)
+	return append(n, row)
+}
+
+/*
+Concat returns a new [][]float64 made by joining the columns of two
+[][]float64s side by side, which is also known as horizontal stacking. For
+example:
+
+	m := [][]float64{{1.0}, {2.0}}
+	n := [][]float64{{3.0, 4.0}, {5.0, 6.0}}
+	o := mat.Concat(m, n) // o is [[1.0, 3.0, 4.0], [2.0, 5.0, 6.0]]
+
+The two [][]float64s must have the same number of rows. Every row of the
+result is newly allocated, so the passed [][]float64s are not mutated in
+this function, and do not share any memory with the result.
+*/
+func Concat(m, n [][]float64) [][]float64 {
+	if len(m) != len(n) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the first argument is %d,\n"
+		s += "while the number of rows of the second argument is %d. They must match.\n"
+		s = fmt.Sprintf(s, "Concat()", len(m), len(n))
+		panic(s)
+	}
+	o := make([][]float64, len(m))
+	for i := range m {
+		o[i] = make([]float64, len(m[i])+len(n[i]))
+		copy(o[i], m[i])
+		copy(o[i][len(m[i]):], n[i])
+	}
+	return o
+}
+
+/*
+VStack returns a new [][]float64 made by placing the rows of the second
+[][]float64 below the rows of the first. For example:
+
+	m := [][]float64{{1.0, 2.0}}
+	n := [][]float64{{3.0, 4.0}, {5.0, 6.0}}
+	o := mat.VStack(m, n) // o is [[1.0, 2.0], [3.0, 4.0], [5.0, 6.0]]
+
+The two [][]float64s must have the same number of columns, and are assumed
+to be non-jagged. Every row of the result is a copy, so the passed
+[][]float64s are not mutated in this function, and do not share any memory
+with the result.
+*/
+func VStack(m, n [][]float64) [][]float64 {
+	if len(m) > 0 && len(n) > 0 && len(m[0]) != len(n[0]) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of columns of the first argument is %d,\n"
+		s += "while the number of columns of the second argument is %d. They must match.\n"
+		s = fmt.Sprintf(s, "VStack()", len(m[0]), len(n[0]))
+		panic(s)
+	}
+	o := make([][]float64, 0, len(m)+len(n))
+	o = append(o, Clone(m)...)
+	return append(o, Clone(n)...)
+}
+
+/*
+Convolve2D returns the two dimensional, discrete convolution of a [][]float64
+with a kernel, which is also a [][]float64. This is a true convolution, meaning
+that the kernel is flipped both vertically and horizontally before it is slid
+across the [][]float64. For the cross-correlation, where the kernel is not
+flipped, look at mat.Correlate2D().
+
+The shape of the result depends on the passed mode. If m has r rows and c
+columns, and the kernel has kr rows and kc columns, then:
+
+	mat.Convolve2D(m, k, "full") // (r+kr-1) by (c+kc-1), every point of overlap
+	mat.Convolve2D(m, k, "same") // r by c, centered on the "full" result
+	mat.Convolve2D(m, k, "valid") // (r-kr+1) by (c-kc+1), points of complete overlap
+
+In the "same" mode, m is effectively padded with zeros so that the result
+has the same shape as m. In the "valid" mode the kernel cannot be larger than
+m in either dimension. Both passed [][]float64s are assumed to be non-jagged,
+and neither of them is mutated in this function.
+*/
+func Convolve2D(m, kernel [][]float64, mode string) [][]float64 {
+	return conv2D(m, kernel, mode, "Convolve2D()")
+}
+
+/*
+Correlate2D returns the two dimensional cross-correlation of a [][]float64
+with a kernel. This is identical to mat.Convolve2D(), except that the kernel
+is not flipped before it is slid across the [][]float64, which is what is
+commonly used for image filtering and in convolutional neural networks. The
+modes "full", "same", and "valid" have the same meaning as in
+mat.Convolve2D().
+
+Both passed [][]float64s are assumed to be non-jagged, and neither of them is
+mutated in this function.
+*/
+func Correlate2D(m, kernel [][]float64, mode string) [][]float64 {
+	if len(kernel) == 0 || len(kernel[0]) == 0 {
+		return conv2D(m, kernel, mode, "Correlate2D()")
+	}
+	flipped := New(len(kernel), len(kernel[0]))
+	for i := range kernel {
+		for j := range kernel[i] {
+			flipped[len(kernel)-1-i][len(kernel[0])-1-j] = kernel[i][j]
+		}
+	}
+	return conv2D(m, flipped, mode, "Correlate2D()")
+}
+
+// conv2D holds the shared logic of Convolve2D and Correlate2D. The name of
+// the calling function is passed in for the error messages.
+func conv2D(m, kernel [][]float64, mode, name string) [][]float64 {
+	if len(m) == 0 || len(m[0]) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the first [][]float64 is empty.\n"
+		s = fmt.Sprintf(s, name)
+		panic(s)
+	}
+	if len(kernel) == 0 || len(kernel[0]) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the kernel is empty.\n"
+		s = fmt.Sprintf(s, name)
+		panic(s)
+	}
+	r, c := len(m), len(m[0])
+	kr, kc := len(kernel), len(kernel[0])
+	var rows, cols, offR, offC int
+	switch mode {
+	case "full":
+		rows, cols = r+kr-1, c+kc-1
+	case "same":
+		rows, cols = r, c
+		offR, offC = (kr-1)/2, (kc-1)/2
+	case "valid":
+		if kr > r || kc > c {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the kernel is %d by %d, which is larger than the\n"
+			s += "%d by %d [][]float64. This is not allowed in \"valid\" mode.\n"
+			s = fmt.Sprintf(s, name, kr, kc, r, c)
+			panic(s)
+		}
+		rows, cols = r-kr+1, c-kc+1
+		offR, offC = kr-1, kc-1
+	default:
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, unknown mode %q. The mode must be one of \"full\",\n"
+		s += "\"same\", or \"valid\".\n"
+		s = fmt.Sprintf(s, name, mode)
+		panic(s)
+	}
+	o := New(rows, cols)
+	for i := range o {
+		for j := range o[i] {
+			for a := range kernel {
+				x := i + offR - a
+				if x < 0 || x >= r {
+					continue
+				}
+				for b := range kernel[a] {
+					y := j + offC - b
+					if y < 0 || y >= c {
+						continue
+					}
+					o[i][j] += m[x][y] * kernel[a][b]
+				}
+			}
+		}
+	}
+	return o
+}
+
+/*
+Pad returns a copy of a [][]float64 which is surrounded by the requested
+number of rows and columns, where all of the new elements are set to the
+passed value. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.Pad(m, 1, 0, 0, 2, 9.0)
+	// n is [[9.0, 9.0, 9.0, 9.0], [1.0, 2.0, 9.0, 9.0], [3.0, 4.0, 9.0, 9.0]]
+
+None of the pad counts may be negative. The passed [][]float64 is assumed to
+be non-jagged, and it is not mutated in this function.
+*/
+func Pad(m [][]float64, top, bottom, left, right int, val float64) [][]float64 {
+	pads := []int{top, bottom, left, right}
+	names := []string{"top", "bottom", "left", "right"}
+	for i := range pads {
+		if pads[i] < 0 {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the %s pad count must not be negative, but received %d.\n"
+			s = fmt.Sprintf(s, "Pad()", names[i], pads[i])
+			panic(s)
+		}
+	}
+	c := 0
+	if len(m) > 0 {
+		c = len(m[0])
+	}
+	n := make([][]float64, top+len(m)+bottom)
+	for i := range n {
+		n[i] = make([]float64, left+c+right)
+		for j := range n[i] {
+			n[i][j] = val
+		}
+	}
+	for i := range m {
+		copy(n[top+i][left:], m[i])
+	}
+	return n
+}
+
+/*
+Tile returns a new [][]float64 made up of copies of the passed [][]float64,
+repeated vReps times vertically, and hReps times horizontally. For example:
+
+	m := [][]float64{{1.0, 2.0}}
+	n := mat.Tile(m, 2, 3)
+	// n is [[1.0, 2.0, 1.0, 2.0, 1.0, 2.0], [1.0, 2.0, 1.0, 2.0, 1.0, 2.0]]
+
+Both repetition counts must be greater than 0. The passed [][]float64 is
+not mutated in this function.
+*/
+func Tile(m [][]float64, vReps, hReps int) [][]float64 {
+	if vReps <= 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of vertical repetitions must be greater than\n"
+		s += "'0', but received %d.\n"
+		s = fmt.Sprintf(s, "Tile()", vReps)
+		panic(s)
+	}
+	if hReps <= 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of horizontal repetitions must be greater than\n"
+		s += "'0', but received %d.\n"
+		s = fmt.Sprintf(s, "Tile()", hReps)
+		panic(s)
+	}
+	n := make([][]float64, vReps*len(m))
+	for i := range n {
+		row := m[i%len(m)]
+		n[i] = make([]float64, 0, hReps*len(row))
+		for j := 0; j < hReps; j++ {
+			n[i] = append(n[i], row...)
+		}
+	}
+	return n
+}
+
+/*
+Rot90 returns a copy of a [][]float64 which is rotated counterclockwise by
+k times 90 degrees. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.Rot90(m, 1) // n is [[2.0, 4.0], [1.0, 3.0]]
+
+The passed k may be negative, in which case the rotation is clockwise. When k
+is a multiple of 4, an unchanged copy of the [][]float64 is returned. The
+passed [][]float64 is assumed to be non-jagged, and it is not mutated in this
+function.
+*/
+func Rot90(m [][]float64, k int) [][]float64 {
+	k = ((k % 4) + 4) % 4
+	if k == 0 || len(m) == 0 {
+		return Clone(m)
+	}
+	r, c := len(m), len(m[0])
+	var n [][]float64
+	switch k {
+	case 1:
+		n = New(c, r)
+		for i := range m {
+			for j := range m[i] {
+				n[c-1-j][i] = m[i][j]
+			}
+		}
+	case 2:
+		n = New(r, c)
+		for i := range m {
+			for j := range m[i] {
+				n[r-1-i][c-1-j] = m[i][j]
+			}
+		}
+	case 3:
+		n = New(c, r)
+		for i := range m {
+			for j := range m[i] {
+				n[j][r-1-i] = m[i][j]
+			}
+		}
+	}
+	return n
+}
+
+/*
+FlipV returns a copy of a [][]float64 with the order of its rows reversed,
+such that the first row becomes the last. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.FlipV(m) // n is [[3.0, 4.0], [1.0, 2.0]]
+
+The passed [][]float64 is not mutated in this function.
+*/
+func FlipV(m [][]float64) [][]float64 {
+	n := make([][]float64, len(m))
+	for i := range m {
+		n[len(m)-1-i] = make([]float64, len(m[i]))
+		copy(n[len(m)-1-i], m[i])
+	}
+	return n
+}
+
+/*
+FlipH returns a copy of a [][]float64 with the order of the elements in each
+row reversed, such that the first column becomes the last. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.FlipH(m) // n is [[2.0, 1.0], [4.0, 3.0]]
+
+The passed [][]float64 is not mutated in this function.
+*/
+func FlipH(m [][]float64) [][]float64 {
+	n := make([][]float64, len(m))
+	for i := range m {
+		n[i] = make([]float64, len(m[i]))
+		for j := range m[i] {
+			n[i][len(m[i])-1-j] = m[i][j]
+		}
+	}
+	return n
+}
+
+/*
+AddCol adds to each row of a [][]float64 the corresponding entry of a
+[]float64, such that every element of row i is increased by col[i]. This is
+the column-wise counterpart of passing a []float64 to mat.Add(), which
+instead broadcasts the []float64 across each row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.AddCol(m, []float64{10.0, 20.0}) // n is [[11.0, 12.0], [23.0, 24.0]]
+
+The length of the []float64 must equal the number of rows of the [][]float64.
+The passed arguments are not mutated in this function.
+*/
+func AddCol(m [][]float64, col []float64) [][]float64 {
+	if len(col) != len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the [][]float64 is %d, but the\n"
+		s += "length of the []float64 is %d. They must match.\n"
+		s = fmt.Sprintf(s, "AddCol()", len(m), len(col))
+		panic(s)
+	}
+	n := Clone(m)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] += col[i]
+		}
+	}
+	return n
+}
+
+/*
+SubCol subtracts from each row of a [][]float64 the corresponding entry of a
+[]float64, such that every element of row i is decreased by col[i]. This is
+the column-wise counterpart of passing a []float64 to mat.Sub(), which
+instead broadcasts the []float64 across each row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.SubCol(m, []float64{1.0, 3.0}) // n is [[0.0, 1.0], [0.0, 1.0]]
+
+The length of the []float64 must equal the number of rows of the [][]float64.
+The passed arguments are not mutated in this function.
+*/
+func SubCol(m [][]float64, col []float64) [][]float64 {
+	if len(col) != len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the [][]float64 is %d, but the\n"
+		s += "length of the []float64 is %d. They must match.\n"
+		s = fmt.Sprintf(s, "SubCol()", len(m), len(col))
+		panic(s)
+	}
+	n := Clone(m)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] -= col[i]
+		}
+	}
+	return n
+}
+
+/*
+MulCol multiplies each row of a [][]float64 by the corresponding entry of a
+[]float64, such that every element of row i is multiplied by col[i]. This is
+the column-wise counterpart of passing a []float64 to mat.Mul(), which
+instead broadcasts the []float64 across each row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.MulCol(m, []float64{2.0, 10.0}) // n is [[2.0, 4.0], [30.0, 40.0]]
+
+The length of the []float64 must equal the number of rows of the [][]float64.
+The passed arguments are not mutated in this function.
+*/
+func MulCol(m [][]float64, col []float64) [][]float64 {
+	if len(col) != len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the [][]float64 is %d, but the\n"
+		s += "length of the []float64 is %d. They must match.\n"
+		s = fmt.Sprintf(s, "MulCol()", len(m), len(col))
+		panic(s)
+	}
+	n := Clone(m)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] *= col[i]
+		}
+	}
+	return n
+}
+
+/*
+DivCol divides each row of a [][]float64 by the corresponding entry of a
+[]float64, such that every element of row i is divided by col[i]. This is
+the column-wise counterpart of passing a []float64 to mat.Div(), which
+instead broadcasts the []float64 across each row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.DivCol(m, []float64{1.0, 2.0}) // n is [[1.0, 2.0], [1.5, 2.0]]
+
+The length of the []float64 must equal the number of rows of the [][]float64.
+None of the elements of the []float64 can be 0.0, and such condition will
+cause a panic. The passed arguments are not mutated in this function.
+*/
+func DivCol(m [][]float64, col []float64) [][]float64 {
+	if len(col) != len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the [][]float64 is %d, but the\n"
+		s += "length of the []float64 is %d. They must match.\n"
+		s = fmt.Sprintf(s, "DivCol()", len(m), len(col))
+		panic(s)
+	}
+	for i := range col {
+		if col[i] == 0.0 {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the passed []float64 contains 0.0 at index %d.\n"
+			s = fmt.Sprintf(s, "DivCol()", i)
+			panic(s)
+		}
+	}
+	n := Clone(m)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] /= col[i]
+		}
+	}
+	return n
+}
+
+/*
+RSub subtracts each element of a [][]float64 from the passed value, which is
+the reverse of mat.Sub(m, val). For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.RSub(m, 1.0) // n is [[0.0, -1.0], [-2.0, -3.0]]
+
+The original [][]float64 is not mutated in this function.
+*/
+func RSub(m [][]float64, val float64) [][]float64 {
+	n := Clone(m)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] = val - n[i][j]
+		}
+	}
+	return n
+}
+
+/*
+RDiv divides the passed value by each element of a [][]float64, which is the
+reverse of mat.Div(m, val). For example:
+
+	m := [][]float64{{1.0, 2.0}, {4.0, 8.0}}
+	n := mat.RDiv(m, 1.0) // n is [[1.0, 0.5], [0.25, 0.125]]
+
+None of the elements of the [][]float64 can be 0.0, and such condition will
+cause a panic. The original [][]float64 is not mutated in this function.
+*/
+func RDiv(m [][]float64, val float64) [][]float64 {
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] == 0.0 {
+				fmt.Println("\ngocrunch/mat error.")
+				s := "In mat.%v, the passed [][]float64 contains 0.0 at [%d][%d].\n"
+				s = fmt.Sprintf(s, "RDiv()", i, j)
+				panic(s)
+			}
+		}
+	}
+	n := Clone(m)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] = val / n[i][j]
+		}
+	}
+	return n
+}
+
+/*
+CumSum returns the cumulative sum of a [][]float64 along the passed axis. If
+the axis is 0, then the sum is accumulated down each column, and if the axis
+is 1, then the sum is accumulated across each row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := mat.CumSum(m, 0) // n is [[1.0, 2.0], [4.0, 6.0]]
+	n = mat.CumSum(m, 1) // n is [[1.0, 3.0], [3.0, 7.0]]
+
+The passed [][]float64 is assumed to be non-jagged, and it is not mutated in
+this function.
+*/
+func CumSum(m [][]float64, axis int) [][]float64 {
+	n := Clone(m)
+	switch axis {
+	case 0:
+		for i := 1; i < len(n); i++ {
+			for j := range n[i] {
+				n[i][j] += n[i-1][j]
+			}
+		}
+	case 1:
+		for i := range n {
+			for j := 1; j < len(n[i]); j++ {
+				n[i][j] += n[i][j-1]
+			}
+		}
+	default:
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s the axis must be 0 for accumulating down the columns, or\n"
+		s += "1 for accumulating across the rows, but %d was passed."
+		s = fmt.Sprintf(s, "CumSum()", axis)
+		panic(s)
+	}
+	return n
+}
+
+/*
+ForeachParallel applies a given function to each element of a [][]float64,
+just like mat.Foreach(), but splits the rows of the [][]float64 across the
+passed number of worker goroutines. If the number of workers is 0, then
+runtime.NumCPU() workers are used. This is worthwhile when the passed
+function is expensive, or the [][]float64 is large. For example:
+
+	n := mat.ForeachParallel(math.Sqrt, m, 4)
+
+Since each worker writes to its own rows of the result, the passed function
+is the only thing that needs to be safe for concurrent use. The resultant
+[][]float64 is returned, leaving the orginal [][]float64 intact.
+*/
+func ForeachParallel(f ElementFunc, m [][]float64, workers int) [][]float64 {
+	if workers < 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of workers cannot be negative, but received %d.\n"
+		s = fmt.Sprintf(s, "ForeachParallel()", workers)
+		panic(s)
+	}
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	n := make([][]float64, len(m))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(m); i += workers {
+				n[i] = make([]float64, len(m[i]))
+				for j := range m[i] {
+					n[i][j] = f(m[i][j])
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	return n
+}
+
+/*
+SumParallel returns the sum of all elements in a [][]float64, just like
+mat.Sum(m), but sums contiguous blocks of rows concurrently using the passed
+number of worker goroutines. If the number of workers is 0, then
+runtime.NumCPU() workers are used.
+
+The partial sums are always combined in the order of the rows, so for a given
+number of workers the result is deterministic. For integer valued data the
+result is identical to that of mat.Sum(m); otherwise it may differ from it in
+the last few bits due to the different order of the additions.
+
+The original [][]float64 is not mutated in this function.
+*/
+func SumParallel(m [][]float64, workers int) float64 {
+	if workers < 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of workers cannot be negative, but received %d.\n"
+		s = fmt.Sprintf(s, "SumParallel()", workers)
+		panic(s)
+	}
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(m) {
+		workers = len(m)
+	}
+	if workers == 0 {
+		return 0.0
+	}
+	chunk := (len(m) + workers - 1) / workers
+	partials := make([]float64, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			end := (w + 1) * chunk
+			if end > len(m) {
+				end = len(m)
+			}
+			sum := 0.0
+			for i := w * chunk; i < end; i++ {
+				for j := range m[i] {
+					sum += m[i][j]
+				}
+			}
+			partials[w] = sum
+		}(w)
+	}
+	wg.Wait()
+	sum := 0.0
+	for i := range partials {
+		sum += partials[i]
+	}
+	return sum
+}
+
+/*
+AvgParallel returns the average value of all the elements in a [][]float64,
+just like mat.Avg(m), but computes the sum using mat.SumParallel() with the
+passed number of workers. If the number of workers is 0, then
+runtime.NumCPU() workers are used.
+
+The original [][]float64 is not mutated in this function.
+*/
+func AvgParallel(m [][]float64, workers int) float64 {
+	numItems := 0
+	for i := range m {
+		numItems += len(m[i])
+	}
+	return SumParallel(m, workers) / float64(numItems)
+}
+
+/*
+Reduce folds a function over all elements of a [][]float64 in row-major
+order, starting from the passed initial value. The function receives the
+accumulated value so far, and the current element, and returns the new
+accumulated value. For example, the sum of all elements is given by:
+
+	add := func(acc, val float64) float64 {
+		return acc + val
+	}
+	sum := mat.Reduce(m, 0.0, add)
+
+The original [][]float64 is not mutated in this function.
+*/
+func Reduce(m [][]float64, init float64, f func(acc, val float64) float64) float64 {
+	acc := init
+	for i := range m {
+		for j := range m[i] {
+			acc = f(acc, m[i][j])
+		}
+	}
+	return acc
+}
+
+/*
+IsDiagonal checks if a [][]float64 is a diagonal matrix. That means that it
+is square, and that all of the elements off of its diagonal are within the
+passed tolerance of 0.0. For example:
+
+	mat.IsDiagonal(mat.I(3), 0.0) // true
+	mat.IsDiagonal([][]float64{{1.0, 1e-9}, {0.0, 2.0}}, 1e-6) // true
+
+This function returns false as soon as the first element which is too far
+from 0.0 is found, so it is cheap for most non-diagonal matrices.
+*/
+func IsDiagonal(m [][]float64, tol float64) bool {
+	for i := range m {
+		if len(m[i]) != len(m) {
+			return false
+		}
+	}
+	for i := range m {
+		for j := range m[i] {
+			if i != j && math.Abs(m[i][j]) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+/*
+DotBanded is the matrix product of two [][]float64s, just like mat.Dot(),
+where the first [][]float64 is assumed to be a banded matrix with the passed
+half-bandwidth. This means that the element m[i][k] may only be non-zero
+when i-bandwidth <= k <= i+bandwidth. For example, a tridiagonal matrix has
+a half-bandwidth of 1, and a diagonal matrix has a half-bandwidth of 0.
+
+The multiplications involving the elements outside of the band are skipped,
+which makes this function dramatically faster than mat.Dot() for narrow
+bands, while producing identical results. The first [][]float64 is checked
+before the multiplication, and if any element outside of the band is
+non-zero, this function will panic. The bandwidth cannot be negative.
+
+Both passed [][]float64s are assumed to be non-jagged, and neither of them is
+mutated in this function.
+*/
+func DotBanded(m, n [][]float64, bandwidth int) [][]float64 {
+	if bandwidth < 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the bandwidth cannot be negative, but received %d.\n"
+		s = fmt.Sprintf(s, "DotBanded()", bandwidth)
+		panic(s)
+	}
+	if len(m[0]) != len(n) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of elements in the first row of the first\n"
+		s += "argument is %d, while the len of the second argument is %d. They\n"
+		s += "must match.\n"
+		s = fmt.Sprintf(s, "DotBanded()", len(m[0]), len(n))
+		panic(s)
+	}
+	for i := range m {
+		for k := range m[i] {
+			if (k < i-bandwidth || k > i+bandwidth) && m[i][k] != 0.0 {
+				fmt.Println("\ngocrunch/mat error.")
+				s := "In mat.%s, the element at [%d][%d] is %f, which is outside of\n"
+				s += "the band with half-bandwidth %d. It must be 0.0.\n"
+				s = fmt.Sprintf(s, "DotBanded()", i, k, m[i][k], bandwidth)
+				panic(s)
+			}
+		}
+	}
+	res := New(len(m), len(n[0]))
+	for i := range m {
+		lo := i - bandwidth
+		if lo < 0 {
+			lo = 0
+		}
+		hi := i + bandwidth + 1
+		if hi > len(m[i]) {
+			hi = len(m[i])
+		}
+		for k := lo; k < hi; k++ {
+			if m[i][k] == 0.0 {
+				continue
+			}
+			for j := range n[k] {
+				res[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return res
+}
+
+/*
+TInPlace transposes a square [][]float64 in place, by swapping each element
+above the diagonal with the corresponding element below it. Unlike mat.T(),
+no new [][]float64 is allocated, which makes this function preferable when
+the original is no longer needed. The passed [][]float64 must be square,
+otherwise this function will panic.
+
+The passed [][]float64 is mutated in this function.
+*/
+func TInPlace(m [][]float64) {
+	for i := range m {
+		if len(m[i]) != len(m) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the [][]float64 must be square, but it has %d rows,\n"
+			s += "while row %d has %d entries.\n"
+			s = fmt.Sprintf(s, "TInPlace()", len(m), i, len(m[i]))
+			panic(s)
+		}
+	}
+	for i := range m {
+		for j := i + 1; j < len(m); j++ {
+			m[i][j], m[j][i] = m[j][i], m[i][j]
+		}
+	}
+}
+
+/*
+SoftmaxRows returns a new [][]float64 where the softmax function has been
+applied to each row of the passed [][]float64 independently. This means that
+each element is exponentiated, and then divided by the sum of the
+exponentials in its row, such that each row of the result sums to 1.0. For
+example:
+
+	m := [][]float64{{1.0, 1.0}, {0.0, 0.0}}
+	n := mat.SoftmaxRows(m) // n is [[0.5, 0.5], [0.5, 0.5]]
+
+To avoid overflow, the maximum element of each row is subtracted from that
+row before it is exponentiated, which does not change the result. None of
+the rows can be empty. The original [][]float64 is not mutated in this
+function.
+*/
+func SoftmaxRows(m [][]float64) [][]float64 {
+	n := make([][]float64, len(m))
+	for i := range m {
+		if len(m[i]) == 0 {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, row %d is empty.\n"
+			s = fmt.Sprintf(s, "SoftmaxRows()", i)
+			panic(s)
+		}
+		max := m[i][0]
+		for j := range m[i] {
+			if m[i][j] > max {
+				max = m[i][j]
+			}
+		}
+		n[i] = make([]float64, len(m[i]))
+		sum := 0.0
+		for j := range m[i] {
+			n[i][j] = math.Exp(m[i][j] - max)
+			sum += n[i][j]
+		}
+		for j := range n[i] {
+			n[i][j] /= sum
+		}
+	}
+	return n
+}
+
+/*
+PairwiseDist returns the Euclidean distances between every pair of rows of a
+[][]float64. The result is a symmetric, square [][]float64, where the element
+at [i][j] is the distance between row i and row j of the passed [][]float64,
+and all elements along the diagonal are 0.0. For example:
+
+	m := [][]float64{{0.0, 0.0}, {3.0, 4.0}}
+	d := mat.PairwiseDist(m) // d is [[0.0, 5.0], [5.0, 0.0]]
+
+For other distance measures, look at mat.PairwiseDistFunc(). The passed
+[][]float64 is assumed to be non-jagged, and it is not mutated in this
+function.
+*/
+func PairwiseDist(m [][]float64) [][]float64 {
+	euclidean := func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			d := a[i] - b[i]
+			sum += d * d
+		}
+		return math.Sqrt(sum)
+	}
+	return PairwiseDistFunc(m, euclidean)
+}
+
+/*
+PairwiseDistFunc returns the distances between every pair of rows of a
+[][]float64, just like mat.PairwiseDist(), but using the passed distance
+function. For example, the Manhattan distances are given by:
+
+	manhattan := func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			sum += math.Abs(a[i] - b[i])
+		}
+		return sum
+	}
+	d := mat.PairwiseDistFunc(m, manhattan)
+
+The distance function is assumed to be symmetric, so it is only called once
+for each pair of rows, and never for a row with itself. The diagonal of the
+result is set to 0.0. The passed [][]float64 is not mutated in this
+function, and neither should the passed distance function mutate the rows
+which it receives.
+*/
+func PairwiseDistFunc(m [][]float64, d func(a, b []float64) float64) [][]float64 {
+	n := New(len(m))
+	for i := range m {
+		for j := i + 1; j < len(m); j++ {
+			n[i][j] = d(m[i], m[j])
+			n[j][i] = n[i][j]
+		}
+	}
+	return n
+}
+
+/*
+Cov returns the sample covariance matrix of a [][]float64, where each column
+is treated as a variable, and each row as an observation of all variables.
+The result is a square, symmetric [][]float64 with one row and column per
+column of the passed [][]float64, where the element at [i][j] is the
+covariance between columns i and j, and the diagonal holds the variance of
+each column.
+
+This function uses N-1 in the denominator, where N is the number of rows,
+which gives the unbiased estimate for a sample of a larger population. Thus
+the passed [][]float64 must have at least 2 rows. For the population
+covariance, which uses N in the denominator, look at mat.CovPop().
+
+The passed [][]float64 is assumed to be non-jagged, and it is not mutated in
+this function.
+*/
+func Cov(m [][]float64) [][]float64 {
+	if len(m) < 2 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, at least 2 rows are needed, but received %d.\n"
+		s = fmt.Sprintf(s, "Cov()", len(m))
+		panic(s)
+	}
+	return cov(m, float64(len(m)-1))
+}
+
+/*
+CovPop returns the population covariance matrix of a [][]float64, which is
+identical to mat.Cov(), except that N is used in the denominator instead of
+N-1, where N is the number of rows. The passed [][]float64 must have at
+least 1 row.
+
+The passed [][]float64 is assumed to be non-jagged, and it is not mutated in
+this function.
+*/
+func CovPop(m [][]float64) [][]float64 {
+	if len(m) < 1 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, at least 1 row is needed, but received %d.\n"
+		s = fmt.Sprintf(s, "CovPop()", len(m))
+		panic(s)
+	}
+	return cov(m, float64(len(m)))
+}
+
+// cov computes the covariance matrix of the columns of m, dividing the sums
+// of the products of the deviations by denom.
+func cov(m [][]float64, denom float64) [][]float64 {
+	cols := len(m[0])
+	means := make([]float64, cols)
+	for j := range means {
+		means[j] = Avg(m, 1, j)
+	}
+	c := New(cols)
+	for i := 0; i < cols; i++ {
+		for j := i; j < cols; j++ {
+			sum := 0.0
+			for k := range m {
+				sum += (m[k][i] - means[i]) * (m[k][j] - means[j])
+			}
+			c[i][j] = sum / denom
+			c[j][i] = c[i][j]
+		}
+	}
+	return c
+}
+
+/*
+Corr returns the Pearson correlation matrix of a [][]float64, where each
+column is treated as a variable, and each row as an observation of all
+variables. The result is a square, symmetric [][]float64 with one row and
+column per column of the passed [][]float64, where the element at [i][j] is
+the correlation between columns i and j. The diagonal is exactly 1.0, and
+all other elements are clamped to [-1.0, 1.0] to remove tiny floating point
+overshoots.
+
+The correlation with a column whose variance is 0.0 is undefined, and so all
+elements in the row and column of such a column, including its diagonal
+element, are set to NaN rather than causing a panic.
+
+The passed [][]float64 must have at least 1 row, it is assumed to be
+non-jagged, and it is not mutated in this function.
+*/
+func Corr(m [][]float64) [][]float64 {
+	if len(m) < 1 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, at least 1 row is needed, but received %d.\n"
+		s = fmt.Sprintf(s, "Corr()", len(m))
+		panic(s)
+	}
+	c := cov(m, float64(len(m)))
+	std := make([]float64, len(c))
+	for i := range c {
+		std[i] = math.Sqrt(c[i][i])
+	}
+	for i := range c {
+		for j := range c[i] {
+			switch {
+			case std[i] == 0.0 || std[j] == 0.0:
+				c[i][j] = math.NaN()
+			case i == j:
+				c[i][j] = 1.0
+			default:
+				c[i][j] = math.Max(-1.0, math.Min(1.0, c[i][j]/(std[i]*std[j])))
+			}
+		}
+	}
+	return c
+}
+
+/*
+PCA performs a principal component analysis of a [][]float64, where each
+column is treated as a variable, and each row as an observation. The columns
+are first centered by subtracting their averages, and then the covariance
+matrix of the centered data is formed using mat.Cov(). The eigenvectors of
+the covariance matrix with the largest eigenvalues are the principal
+components, and the centered data is projected onto the passed number of
+them. For example:
+
+	projected, variance := mat.PCA(m, 2)
+
+Here projected has the same number of rows as m, and 2 columns, holding the
+coordinates of each observation along the first and second principal
+components. The returned variance holds the eigenvalue of each of the
+returned components, which is the variance of the data along it, in
+descending order. The sign of each component is chosen such that its
+largest element (by absolute value) is positive.
+
+The number of components must be between 1 and the number of columns of the
+passed [][]float64, which must have at least 2 rows. The passed [][]float64
+is assumed to be non-jagged, and it is not mutated in this function.
+*/
+func PCA(m [][]float64, components int) ([][]float64, []float64) {
+	if len(m) < 2 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, at least 2 rows are needed, but received %d.\n"
+		s = fmt.Sprintf(s, "PCA()", len(m))
+		panic(s)
+	}
+	if components < 1 || components > len(m[0]) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of components must be in [1, %d], the number\n"
+		s += "of columns, but received %d.\n"
+		s = fmt.Sprintf(s, "PCA()", len(m[0]), components)
+		panic(s)
+	}
+	means := make([]float64, len(m[0]))
+	for j := range means {
+		means[j] = Avg(m, 1, j)
+	}
+	centered := Sub(m, means)
+	c := Cov(centered)
+	scale := 0.0
+	for i := range c {
+		for j := range c[i] {
+			scale += c[i][j] * c[i][j]
+		}
+	}
+	values, vectors := jacobiEig(c, 100, 1e-14*math.Sqrt(scale))
+	values, vectors = sortEig(values, vectors)
+	w := make([][]float64, len(vectors))
+	for i := range vectors {
+		w[i] = vectors[i][:components]
+	}
+	return Dot(centered, w), values[:components]
+}
+
+// jacobiEig finds the eigenvalues and eigenvectors of the real, symmetric
+// matrix m using the cyclic Jacobi method. Sweeps of rotations, each zeroing
+// one off-diagonal element, are applied until the square root of the sum of
+// the squares of the off-diagonal elements is at most tol, or iters sweeps
+// have been done. The eigenvectors are the columns of the returned matrix,
+// and the eigenvalues are in no particular order. m is not mutated.
+func jacobiEig(m [][]float64, iters int, tol float64) ([]float64, [][]float64) {
+	a := Clone(m)
+	n := len(a)
+	v := I(n)
+	for sweep := 0; sweep < iters; sweep++ {
+		off := 0.0
+		for p := range a {
+			for q := range a[p] {
+				if p != q {
+					off += a[p][q] * a[p][q]
+				}
+			}
+		}
+		if math.Sqrt(off) <= tol {
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0.0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2.0 * a[p][q])
+				t := 1.0 / (math.Abs(theta) + math.Sqrt(theta*theta+1.0))
+				if theta < 0.0 {
+					t = -t
+				}
+				c := 1.0 / math.Sqrt(t*t+1.0)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	return values, v
+}
+
+// eigPairs sorts eigenvalues in descending order, along with the columns of
+// the matrix holding the corresponding eigenvectors.
+type eigPairs struct {
+	values []float64
+	order  []int
+}
+
+func (e eigPairs) Len() int           { return len(e.order) }
+func (e eigPairs) Less(i, j int) bool { return e.values[e.order[i]] > e.values[e.order[j]] }
+func (e eigPairs) Swap(i, j int)      { e.order[i], e.order[j] = e.order[j], e.order[i] }
+
+// sortEig returns copies of the eigenvalues and eigenvector columns sorted
+// by descending eigenvalue. The sign of each eigenvector is flipped if
+// needed, such that its largest element by absolute value is positive.
+func sortEig(values []float64, vectors [][]float64) ([]float64, [][]float64) {
+	e := eigPairs{values, make([]int, len(values))}
+	for i := range e.order {
+		e.order[i] = i
+	}
+	sort.Stable(e)
+	vals := make([]float64, len(values))
+	vecs := New(len(vectors), len(values))
+	for j, k := range e.order {
+		vals[j] = values[k]
+		big := 0.0
+		for i := range vectors {
+			if math.Abs(vectors[i][k]) > math.Abs(big) {
+				big = vectors[i][k]
+			}
+		}
+		for i := range vectors {
+			vecs[i][j] = vectors[i][k]
+			if big < 0.0 {
+				vecs[i][j] = -vecs[i][j]
+			}
+		}
+	}
+	return vals, vecs
+}
+
+/*
+EigSym returns the eigenvalues and eigenvectors of a real, symmetric
+[][]float64, found using the cyclic Jacobi method. In each sweep, every
+off-diagonal element is zeroed in turn by a rotation, until the square root
+of the sum of the squares of the off-diagonal elements is at most tol, or
+iters sweeps have been done. For example:
+
+	values, vectors := mat.EigSym(m, 100, 1e-12)
+
+The eigenvalues are sorted in descending order, and the eigenvector
+belonging to values[i] is the i-th column of vectors, which is given by
+mat.Col(vectors, i). Each eigenvector has a norm of 1.0, and its sign is
+chosen such that its largest element (by absolute value) is positive.
+
+The passed [][]float64 must be square, and symmetric within tol, meaning
+that m[i][j] and m[j][i] differ by at most tol for all i and j. Otherwise,
+this function will panic. The passed [][]float64 is not mutated in this
+function.
+*/
+func EigSym(m [][]float64, iters int, tol float64) ([]float64, [][]float64) {
+	for i := range m {
+		if len(m[i]) != len(m) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the [][]float64 must be square, but it has %d rows,\n"
+			s += "while row %d has %d entries.\n"
+			s = fmt.Sprintf(s, "EigSym()", len(m), i, len(m[i]))
+			panic(s)
+		}
+	}
+	for i := range m {
+		for j := i + 1; j < len(m); j++ {
+			if math.Abs(m[i][j]-m[j][i]) > tol {
+				fmt.Println("\ngocrunch/mat error.")
+				s := "In mat.%s, the [][]float64 is not symmetric: the element at\n"
+				s += "[%d][%d] is %f, while the element at [%d][%d] is %f.\n"
+				s = fmt.Sprintf(s, "EigSym()", i, j, m[i][j], j, i, m[j][i])
+				panic(s)
+			}
+		}
+	}
+	values, vectors := jacobiEig(m, iters, tol)
+	return sortEig(values, vectors)
+}
+
+/*
+Jacobian returns the Jacobian matrix of a vector valued function at the point
+x, approximated using central finite differences with step h. If f returns a
+[]float64 of length r, and x has length c, then the result is an r by c
+[][]float64, where the element at [i][j] is the derivative of the i-th
+output of f with respect to the j-th element of x. For example:
+
+	f := func(x []float64) []float64 {
+		return []float64{x[0] * x[1], x[0] + x[1]}
+	}
+	j := mat.Jacobian(f, []float64{2.0, 3.0}, 0.0) // j is about [[3.0, 2.0], [1.0, 1.0]]
+
+If h is 0.0, then a default step of 1e-6 is used. The step cannot be
+negative, and f must always return a []float64 of the same length. This
+function calls f 2*len(x)+1 times, and x is not mutated in this function.
+*/
+func Jacobian(f func([]float64) []float64, x []float64, h float64) [][]float64 {
+	if h < 0.0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the step cannot be negative, but received %f.\n"
+		s = fmt.Sprintf(s, "Jacobian()", h)
+		panic(s)
+	}
+	if h == 0.0 {
+		h = 1e-6
+	}
+	rows := len(f(x))
+	j := New(rows, len(x))
+	xh := make([]float64, len(x))
+	copy(xh, x)
+	for c := range x {
+		xh[c] = x[c] + h
+		fp := f(xh)
+		xh[c] = x[c] - h
+		fm := f(xh)
+		xh[c] = x[c]
+		if len(fp) != rows || len(fm) != rows {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the passed function returned []float64s of different\n"
+			s += "lengths: %d, %d, and %d.\n"
+			s = fmt.Sprintf(s, "Jacobian()", rows, len(fp), len(fm))
+			panic(s)
+		}
+		for r := range fp {
+			j[r][c] = (fp[r] - fm[r]) / (2.0 * h)
+		}
+	}
+	return j
+}
+
+/*
+WeightedAvgRows returns the weighted average of the rows of a [][]float64,
+where row i is given the weight weights[i]. The result is a []float64 with
+one element per column, where each element is the weighted average of that
+column. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 6.0}}
+	w := []float64{3.0, 1.0}
+	v := mat.WeightedAvgRows(m, w) // v is [1.5, 3.0]
+
+The length of the weights must equal the number of rows of the [][]float64,
+and the sum of the weights cannot be 0.0. The passed [][]float64 is assumed
+to be non-jagged, and the passed arguments are not mutated in this function.
+*/
+func WeightedAvgRows(m [][]float64, weights []float64) []float64 {
+	if len(weights) != len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the [][]float64 is %d, but the\n"
+		s += "length of the weights is %d. They must match.\n"
+		s = fmt.Sprintf(s, "WeightedAvgRows()", len(m), len(weights))
+		panic(s)
+	}
+	total := 0.0
+	for i := range weights {
+		total += weights[i]
+	}
+	if total == 0.0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the sum of the weights is 0.0.\n"
+		s = fmt.Sprintf(s, "WeightedAvgRows()")
+		panic(s)
+	}
+	v := make([]float64, len(m[0]))
+	for i := range m {
+		for j := range v {
+			v[j] += weights[i] * m[i][j]
+		}
+	}
+	for j := range v {
+		v[j] /= total
+	}
+	return v
+}
+
+/*
+MeanCols returns the average of each column of a [][]float64, as a []float64
+with one element per column. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 6.0}}
+	v := mat.MeanCols(m) // v is [2.0, 4.0]
+
+This is equivalent to calling mat.Avg(m, 1, j) for each column j, but walks
+the [][]float64 only once. The passed [][]float64 must not be empty, it is
+assumed to be non-jagged, and it is not mutated in this function.
+*/
+func MeanCols(m [][]float64) []float64 {
+	if len(m) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the [][]float64 is empty.\n"
+		s = fmt.Sprintf(s, "MeanCols()")
+		panic(s)
+	}
+	v := make([]float64, len(m[0]))
+	for i := range m {
+		for j := range v {
+			v[j] += m[i][j]
+		}
+	}
+	for j := range v {
+		v[j] /= float64(len(m))
+	}
+	return v
+}
+
+/*
+MeanRows returns the average of each row of a [][]float64, as a []float64
+with one element per row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 6.0}}
+	v := mat.MeanRows(m) // v is [1.5, 4.5]
+
+This is equivalent to calling mat.Avg(m, 0, i) for each row i. The original
+[][]float64 is not mutated in this function.
+*/
+func MeanRows(m [][]float64) []float64 {
+	v := make([]float64, len(m))
+	for i := range m {
+		sum := 0.0
+		for j := range m[i] {
+			sum += m[i][j]
+		}
+		v[i] = sum / float64(len(m[i]))
+	}
+	return v
+}
+
+/*
+SumCols returns the sum of each column of a [][]float64, as a []float64 with
+one element per column. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 6.0}}
+	v := mat.SumCols(m) // v is [4.0, 8.0]
+
+This is equivalent to calling mat.Sum(m, 1, j) for each column j, but walks
+the [][]float64 only once, in row-major order. The passed [][]float64 must
+not be empty, it is assumed to be non-jagged, and it is not mutated in this
+function.
+*/
+func SumCols(m [][]float64) []float64 {
+	if len(m) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the [][]float64 is empty.\n"
+		s = fmt.Sprintf(s, "SumCols()")
+		panic(s)
+	}
+	v := make([]float64, len(m[0]))
+	for i := range m {
+		for j := range v {
+			v[j] += m[i][j]
+		}
+	}
+	return v
+}
+
+/*
+SumRows returns the sum of each row of a [][]float64, as a []float64 with
+one element per row. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 6.0}}
+	v := mat.SumRows(m) // v is [3.0, 9.0]
+
+This is equivalent to calling mat.Sum(m, 0, i) for each row i. The original
+[][]float64 is not mutated in this function.
+*/
+func SumRows(m [][]float64) []float64 {
+	v := make([]float64, len(m))
+	for i := range m {
+		for j := range m[i] {
+			v[i] += m[i][j]
+		}
+	}
+	return v
+}
+
+/*
+Diff checks to see if two [][]float64s are equal, just like mat.Equal(), but
+also reports where the first difference was found, which is handy when
+debugging numerical code. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := [][]float64{{1.0, 2.0}, {3.0, 5.0}}
+	equal, i, j := mat.Diff(m, n) // false, 1, 1
+
+The rows are checked in order, and the returned indices are interpreted as
+follows:
+
+	true, -1, -1 // the [][]float64s are equal
+	false, -1, -1 // the number of rows differ
+	false, i, -1 // row i has a different number of entries
+	false, i, j // the elements at [i][j] differ
+
+The passed [][]float64s are not mutated in this function.
+*/
+func Diff(m, n [][]float64) (bool, int, int) {
+	if len(m) != len(n) {
+		return false, -1, -1
+	}
+	for i := range m {
+		if len(m[i]) != len(n[i]) {
+			return false, i, -1
+		}
+		for j := range m[i] {
+			if m[i][j] != n[i][j] {
+				return false, i, j
+			}
+		}
+	}
+	return true, -1, -1
+}
+
+/*
+Sprint formats a [][]float64 as a string, with each row on its own line, and
+the elements of each column right-aligned, using the passed number of digits
+after the decimal point. For example:
+
+	m := [][]float64{{1.0, -2.5}, {10.0, 3.0}}
+	fmt.Println(mat.Sprint(m, 2))
+
+prints
+
+	 1.00  -2.50
+	10.00   3.00
+
+A negative precision uses the smallest number of digits necessary to
+represent each value exactly. An empty [][]float64 is formatted as "[]". The
+passed [][]float64 is not mutated in this function.
+*/
+func Sprint(m [][]float64, prec int) string {
+	if len(m) == 0 {
+		return "[]"
+	}
+	strs := make([][]string, len(m))
+	widths := []int{}
+	for i := range m {
+		strs[i] = make([]string, len(m[i]))
+		for j := range m[i] {
+			strs[i][j] = strconv.FormatFloat(m[i][j], 'f', prec, 64)
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(strs[i][j]) > widths[j] {
+				widths[j] = len(strs[i][j])
+			}
+		}
+	}
+	var buf bytes.Buffer
+	for i := range strs {
+		for j := range strs[i] {
+			if j != 0 {
+				buf.WriteString("  ")
+			}
+			fmt.Fprintf(&buf, "%*s", widths[j], strs[i][j])
+		}
+		if i+1 != len(strs) {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+/*
+ToMarkdown formats a [][]float64 as a GitHub flavored Markdown table, using
+the passed column headers and the passed number of digits after the decimal
+point. For example:
+
+	m := [][]float64{{1.0, -2.5}, {10.0, 3.0}}
+	fmt.Println(mat.ToMarkdown(m, []string{"x", "y"}, 2))
+
+prints
+
+	| x | y |
+	| ---: | ---: |
+	| 1.00 | -2.50 |
+	| 10.00 | 3.00 |
+
+If headers is nil, the columns are numbered starting from 0. Otherwise, the
+number of headers must equal the number of columns, and any "|" characters
+in them are escaped. A negative precision uses the smallest number of digits
+necessary to represent each value exactly. The columns are right-aligned.
+The passed [][]float64 must be non-jagged, and it is not mutated in this
+function.
+*/
+func ToMarkdown(m [][]float64, headers []string, prec int) string {
+	cols := len(headers)
+	if headers == nil {
+		if len(m) > 0 {
+			cols = len(m[0])
+		}
+		headers = make([]string, cols)
+		for j := range headers {
+			headers[j] = strconv.Itoa(j)
+		}
+	}
+	for i := range m {
+		if len(m[i]) != cols {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, row %d has %d entries, but there are %d columns.\n"
+			s = fmt.Sprintf(s, "ToMarkdown()", i, len(m[i]), cols)
+			panic(s)
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteString("|")
+	for j := range headers {
+		buf.WriteString(" " + strings.Replace(headers[j], "|", "\\|", -1) + " |")
+	}
+	buf.WriteString("\n|")
+	for j := 0; j < cols; j++ {
+		buf.WriteString(" ---: |")
+	}
+	for i := range m {
+		buf.WriteString("\n|")
+		for j := range m[i] {
+			buf.WriteString(" " + strconv.FormatFloat(m[i][j], 'f', prec, 64) + " |")
+		}
+	}
+	return buf.String()
+}
+
+/*
+Matrix is a [][]float64 with a String method, which lets fmt print it in a
+readable form. All of the functions in this package take and return plain
+[][]float64s, so Matrix is only meant to be used for printing. For example:
+
+	m := mat.I(2)
+	fmt.Println(mat.Wrap(m))
+
+prints
+
+	1  0
+	0  1
+
+Converting between the two types does not copy the underlying data, so the
+original [][]float64 can be recovered with [][]float64(w).
+*/
+type Matrix [][]float64
+
+/*
+Wrap converts a [][]float64 into a Matrix, without copying the underlying
+data. Changes made to the [][]float64 are visible in the returned Matrix,
+and vice versa.
+*/
+func Wrap(m [][]float64) Matrix {
+	return Matrix(m)
+}
+
+/*
+String formats the Matrix with each row on its own line, and the elements
+of each column right-aligned, as done by mat.Sprint() with a negative
+precision.
+*/
+func (m Matrix) String() string {
+	return Sprint(m, -1)
+}
+
+/*
+Cols returns a new [][]float64 made up of the requested columns of the passed
+[][]float64, in the order in which they are requested. For example:
+
+	fmt.Println(m) // [[1.0, 2.0, 3.0], [4.0, 5.0, 6.0]]
+	mat.Cols(m, 2, 0) // [[3.0, 1.0], [6.0, 4.0]]
+
+Just like mat.Col(), negative indices are accepted, so that
+
+	mat.Cols(m, -1) // [[3.0], [6.0]]
+
+The same column can be requested more than once. If no columns are
+requested, the result has one empty row for each row of m. The passed
+[][]float64 is assumed to be non-jagged, and it is not mutated in this
+function.
+*/
+func Cols(m [][]float64, idx ...int) [][]float64 {
+	cols := 0
+	if len(m) > 0 {
+		cols = len(m[0])
+	}
+	for _, x := range idx {
+		if (x >= cols) || (x < -cols) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s the requested column %d is outside of bounds [-%d, %d)\n"
+			s = fmt.Sprintf(s, "Cols()", x, cols, cols)
+			panic(s)
+		}
+	}
+	n := make([][]float64, len(m))
+	for i := range n {
+		n[i] = make([]float64, len(idx))
+	}
+	for k, x := range idx {
+		if x < 0 {
+			x += cols
+		}
+		for i := range m {
+			n[i][k] = m[i][x]
+		}
+	}
+	return n
+}
+
+/*
+Rows returns a new [][]float64 made up of copies of the requested rows of the
+passed [][]float64, in the order in which they are requested. For example:
+
+	fmt.Println(m) // [[1.0, 2.0], [3.0, 4.0], [5.0, 6.0]]
+	mat.Rows(m, 2, 0) // [[5.0, 6.0], [1.0, 2.0]]
+
+Just like mat.Row(), negative indices are accepted, so that
+
+	mat.Rows(m, -1) // [[5.0, 6.0]]
+
+The same row can be requested more than once. The passed [][]float64 is not
+mutated in this function.
+*/
+func Rows(m [][]float64, idx ...int) [][]float64 {
+	for _, x := range idx {
+		if (x >= len(m)) || (x < -len(m)) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s the requested row %d is outside of bounds [-%d, %d)\n"
+			s = fmt.Sprintf(s, "Rows()", x, len(m), len(m))
+			panic(s)
+		}
+	}
+	n := make([][]float64, len(idx))
+	for k, x := range idx {
+		if x < 0 {
+			x += len(m)
+		}
+		n[k] = make([]float64, len(m[x]))
+		copy(n[k], m[x])
+	}
+	return n
+}
+
+/*
+SortRowsBy reorders the rows of a [][]float64 in place, such that the values
+in the passed column are in ascending order. Each row is kept intact, and
+rows with equal values in the column keep their original order. For example:
+
+	m := [][]float64{{3.0, 1.0}, {1.0, 2.0}, {2.0, 3.0}}
+	mat.SortRowsBy(m, 0) // m is [[1.0, 2.0], [2.0, 3.0], [3.0, 1.0]]
+
+Just like mat.Col(), negative column indices are accepted. For descending
+order, look at mat.SortRowsByDesc(). The passed [][]float64 is assumed to be
+non-jagged, and it is mutated in this function.
+*/
+func SortRowsBy(m [][]float64, col int) {
+	sort.Stable(byCol{m, checkSortCol(m, col, "SortRowsBy()"), false})
+}
+
+/*
+SortRowsByDesc reorders the rows of a [][]float64 in place, such that the
+values in the passed column are in descending order. It is otherwise
+identical to mat.SortRowsBy(), and rows with equal values in the column keep
+their original order.
+
+The passed [][]float64 is assumed to be non-jagged, and it is mutated in
+this function.
+*/
+func SortRowsByDesc(m [][]float64, col int) {
+	sort.Stable(byCol{m, checkSortCol(m, col, "SortRowsByDesc()"), true})
+}
+
+// byCol sorts the rows of a [][]float64 by the values in one column.
+type byCol struct {
+	m    [][]float64
+	col  int
+	desc bool
+}
+
+func (b byCol) Len() int      { return len(b.m) }
+func (b byCol) Swap(i, j int) { b.m[i], b.m[j] = b.m[j], b.m[i] }
+func (b byCol) Less(i, j int) bool {
+	if b.desc {
+		return b.m[i][b.col] > b.m[j][b.col]
+	}
+	return b.m[i][b.col] < b.m[j][b.col]
+}
+
+// checkSortCol checks that col is a valid, possibly negative, column index
+// of m, and returns it as a non-negative index.
+func checkSortCol(m [][]float64, col int, name string) int {
+	if len(m) == 0 {
+		return 0
+	}
+	if (col >= len(m[0])) || (col < -len(m[0])) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s the requested column %d is outside of bounds [-%d, %d)\n"
+		s = fmt.Sprintf(s, name, col, len(m[0]), len(m[0]))
+		panic(s)
+	}
+	if col < 0 {
+		col += len(m[0])
+	}
+	return col
+}
+
+/*
+Apply2 applies a function to each pair of corresponding elements of two
+[][]float64s, storing the results in a new [][]float64 which is returned.
+This generalizes mat.Mul(), mat.Add(), mat.Sub(), and mat.Div() to any
+element-wise binary operation. For example:
+
+	m := [][]float64{{1.0, 5.0}, {3.0, 2.0}}
+	n := [][]float64{{4.0, 2.0}, {3.0, 6.0}}
+	o := mat.Apply2(math.Max, m, n) // o is [[4.0, 5.0], [3.0, 6.0]]
+
+The shape of the [][]float64s must be the same (same number of rows, and
+same number of entries in each row). The passed [][]float64s are not
+mutated in this function.
+*/
+func Apply2(f func(a, b float64) float64, m, n [][]float64) [][]float64 {
+	if len(m) != len(n) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%v, the number of the rows of the first slice is %d\n"
+		s += "but the number of rows of the second slice is %d. They must\n"
+		s += "match.\n"
+		s = fmt.Sprintf(s, "Apply2()", len(m), len(n))
+		panic(s)
+	}
+	o := make([][]float64, len(m))
+	for i := range m {
+		if len(m[i]) != len(n[i]) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%v, row number %d of the first [][]float64 has length %d,\n"
+			s += "while row number %d of the second [][]float64 has length %d.\n"
+			s += "The length of each row must match.\n"
+			s = fmt.Sprintf(s, "Apply2()", i, len(m[i]), i, len(n[i]))
+			panic(s)
+		}
+		o[i] = make([]float64, len(m[i]))
+		for j := range m[i] {
+			o[i][j] = f(m[i][j], n[i][j])
+		}
+	}
+	return o
+}
+
+/*
+Rank returns the numerical rank of a [][]float64, which is the number of
+linearly independent rows (or columns). For example:
+
+	m := [][]float64{{1.0, 2.0}, {2.0, 4.0}, {0.0, 1.0}}
+	r := mat.Rank(m, 0.0) // r is 2
+
+The rank is found using Gaussian elimination with partial pivoting, and is
+the number of pivots that are not negligible. A pivot is considered
+negligible when its absolute value is at most tol times the largest absolute
+value in the [][]float64. This makes tol relative to the scale of the
+matrix, so that multiplying every element by a constant does not change the
+rank. If tol is 0.0, a default of max(rows, cols) times the machine epsilon
+is used. Rows with nearly collinear entries may need a larger tol to be
+counted as dependent. The tol cannot be negative.
+
+The [][]float64 does not need to be square, but it is assumed to be
+non-jagged. An empty [][]float64, or one with only zero elements, has a rank
+of 0. The passed [][]float64 is not mutated in this function.
+*/
+func Rank(m [][]float64, tol float64) int {
+	if tol < 0.0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the tol cannot be negative, but received %f.\n"
+		s = fmt.Sprintf(s, "Rank()", tol)
+		panic(s)
+	}
+	if len(m) == 0 || len(m[0]) == 0 {
+		return 0
+	}
+	a := Clone(m)
+	rows, cols := len(a), len(a[0])
+	if tol == 0.0 {
+		eps := math.Nextafter(1.0, 2.0) - 1.0
+		tol = float64(rows) * eps
+		if cols > rows {
+			tol = float64(cols) * eps
+		}
+	}
+	largest := 0.0
+	for i := range a {
+		for j := range a[i] {
+			if math.Abs(a[i][j]) > largest {
+				largest = math.Abs(a[i][j])
+			}
+		}
+	}
+	thresh := tol * largest
+	rank := 0
+	for j := 0; j < cols && rank < rows; j++ {
+		p := rank
+		for i := rank + 1; i < rows; i++ {
+			if math.Abs(a[i][j]) > math.Abs(a[p][j]) {
+				p = i
+			}
+		}
+		if math.Abs(a[p][j]) <= thresh {
+			continue
+		}
+		a[rank], a[p] = a[p], a[rank]
+		for i := rank + 1; i < rows; i++ {
+			f := a[i][j] / a[rank][j]
+			for k := j; k < cols; k++ {
+				a[i][k] -= f * a[rank][k]
+			}
+		}
+		rank++
+	}
+	return rank
+}
+
+/*
+Cond returns an estimate of the condition number of a square [][]float64,
+using the 1-norm, which is the largest absolute column sum. The condition
+number is norm(m) * norm(inverse of m). For example:
+
+	m := [][]float64{{1.0, 0.0}, {0.0, 1e-6}}
+	c := mat.Cond(m) // c is 1e6
+
+A large condition number means that small changes in m, such as rounding
+errors, can cause large changes in the solution of a linear system involving
+m. As a rule of thumb, about log10(c) digits of precision are lost.
+
+If m is singular, or numerically singular, +Inf is returned. The matrix is
+treated as numerically singular when a pivot of its LU decomposition is at
+most n times the machine epsilon times the largest absolute element of m,
+where n is the number of rows, since the inverse computed from such a pivot
+would be dominated by rounding errors. This means that a matrix which is
+invertible, but whose condition number is larger than about 1 / (n * eps),
+also reports +Inf. For example, mat.Cond() of diag(1.0, 1e-16) is +Inf,
+even though its true condition number is 1e16. The passed [][]float64 must
+be square and non-empty, and it is not mutated in this function.
+*/
+func Cond(m [][]float64) float64 {
+	if len(m) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the [][]float64 cannot be empty.\n"
+		s = fmt.Sprintf(s, "Cond()")
+		panic(s)
+	}
+	for i := range m {
+		if len(m[i]) != len(m) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the [][]float64 must be square, but it has %d rows,\n"
+			s += "while row %d has %d entries.\n"
+			s = fmt.Sprintf(s, "Cond()", len(m), i, len(m[i]))
+			panic(s)
+		}
+	}
+	a, perm, _, ok := lu(m)
+	if !ok || tinyPivot(m, a) {
+		return math.Inf(1)
+	}
+	n := len(m)
+	inv := New(n, n)
+	col := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := range col {
+			col[i] = 0.0
+			if perm[i] == j {
+				col[i] = 1.0
+			}
+		}
+		luSolve(a, col)
+		for i := range col {
+			inv[i][j] = col[i]
+		}
+	}
+	return norm1(m) * norm1(inv)
+}
+
+// lu returns the LU decomposition of a square [][]float64 using partial
+// pivoting. L (with an implied unit diagonal) and U are packed into the
+// returned [][]float64, and row i of the result comes from row perm[i] of m.
+// sign is the sign of the permutation, and ok is false if m is singular.
+func lu(m [][]float64) ([][]float64, []int, float64, bool) {
+	a := Clone(m)
+	n := len(a)
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := 1.0
+	for j := 0; j < n; j++ {
+		p := j
+		for i := j + 1; i < n; i++ {
+			if math.Abs(a[i][j]) > math.Abs(a[p][j]) {
+				p = i
+			}
+		}
+		if a[p][j] == 0.0 {
+			return a, perm, sign, false
+		}
+		if p != j {
+			a[p], a[j] = a[j], a[p]
+			perm[p], perm[j] = perm[j], perm[p]
+			sign = -sign
+		}
+		for i := j + 1; i < n; i++ {
+			a[i][j] /= a[j][j]
+			for k := j + 1; k < n; k++ {
+				a[i][k] -= a[i][j] * a[j][k]
+			}
+		}
+	}
+	return a, perm, sign, true
+}
+
+// luSolve solves LUx = b in place, where a holds the packed factors returned
+// by lu, and b has already been permuted to match.
+func luSolve(a [][]float64, b []float64) {
+	for i := range b {
+		for k := 0; k < i; k++ {
+			b[i] -= a[i][k] * b[k]
+		}
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		for k := i + 1; k < len(b); k++ {
+			b[i] -= a[i][k] * b[k]
+		}
+		b[i] /= a[i][i]
+	}
+}
+
+// tinyPivot reports whether a pivot of a, the packed factors returned by
+// lu(m), is at most n times the machine epsilon times the largest absolute
+// element of m, in which case m is numerically singular.
+func tinyPivot(m, a [][]float64) bool {
+	largest := 0.0
+	for i := range m {
+		for j := range m[i] {
+			largest = math.Max(largest, math.Abs(m[i][j]))
+		}
+	}
+	thresh := float64(len(m)) * (math.Nextafter(1.0, 2.0) - 1.0) * largest
+	for i := range a {
+		if math.Abs(a[i][i]) <= thresh {
+			return true
+		}
+	}
+	return false
+}
+
+// norm1 returns the largest absolute column sum of a [][]float64.
+func norm1(m [][]float64) float64 {
+	largest := 0.0
+	for j := range m[0] {
+		sum := 0.0
+		for i := range m {
+			sum += math.Abs(m[i][j])
+		}
+		if sum > largest {
+			largest = sum
+		}
+	}
+	return largest
+}
+
+/*
+DotBatch returns the matrix products of two slices of [][]float64s, where
+the k-th element of the result is mat.Dot(ms[k], ns[k]). For example:
+
+	ms := [][][]float64{mat.I(2), {{1.0, 2.0}}}
+	ns := [][][]float64{{{1.0}, {2.0}}, {{3.0}, {4.0}}}
+	res := mat.DotBatch(ms, ns) // res is [[[1.0], [2.0]], [[11.0]]]
+
+The products are split across runtime.NumCPU() worker goroutines, which
+avoids the overhead of starting goroutines for every call when many small
+matrices are multiplied. The two slices must have the same length, and for
+each k the number of columns of ms[k] must equal the number of rows of
+ns[k]; all pairs are checked before any work is done. Each [][]float64 is
+assumed to be non-empty and non-jagged. The passed arguments are not mutated
+in this function.
+*/
+func DotBatch(ms, ns [][][]float64) [][][]float64 {
+	if len(ms) != len(ns) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of [][]float64s in the first argument is %d,\n"
+		s += "while the number in the second argument is %d. They must match.\n"
+		s = fmt.Sprintf(s, "DotBatch()", len(ms), len(ns))
+		panic(s)
+	}
+	for k := range ms {
+		if len(ms[k][0]) != len(ns[k]) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, at index %d the number of columns of the first\n"
+			s += "[][]float64 is %d, while the number of rows of the second is %d.\n"
+			s += "They must match.\n"
+			s = fmt.Sprintf(s, "DotBatch()", k, len(ms[k][0]), len(ns[k]))
+			panic(s)
+		}
+	}
+	res := make([][][]float64, len(ms))
+	workers := runtime.NumCPU()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for k := w; k < len(ms); k += workers {
+				res[k] = New(len(ms[k]), len(ns[k][0]))
+				dotInto(res[k], ms[k], ns[k])
+			}
+		}(w)
+	}
+	wg.Wait()
+	return res
+}
+
+// dotInto adds the matrix product of m and n to dst, which must already have
+// the correct shape.
+func dotInto(dst, m, n [][]float64) {
+	for i := range m {
+		for k := range m[i] {
+			a := m[i][k]
+			for j := range n[k] {
+				dst[i][j] += a * n[k][j]
+			}
+		}
+	}
+}
+
+/*
+DotInto computes the matrix product of m and n, just like mat.Dot(m, n), but
+writes the result into dst instead of allocating a new [][]float64. For
+example:
+
+	dst := mat.New(2, 2)
+	for i := 0; i < iters; i++ {
+		mat.DotInto(dst, m, n)
+		// use dst...
+	}
+
+Any existing values in dst are overwritten. The number of columns of m must
+equal the number of rows of n, and dst must have len(m) rows with len(n[0])
+entries each. Otherwise, this function will panic. dst must not share any
+rows with m or n. m and n are not mutated in this function.
+*/
+func DotInto(dst, m, n [][]float64) {
+	checkDotInto(dst, m, n, "DotInto()")
+	for i := range dst {
+		for j := range dst[i] {
+			dst[i][j] = 0.0
+		}
+	}
+	dotInto(dst, m, n)
+}
+
+// checkDotInto panics if m and n cannot be multiplied, or if dst does not
+// have the shape of their product.
+func checkDotInto(dst, m, n [][]float64, name string) {
+	if len(m[0]) != len(n) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of columns of the first [][]float64 is %d,\n"
+		s += "while the number of rows of the second is %d. They must match.\n"
+		s = fmt.Sprintf(s, name, len(m[0]), len(n))
+		panic(s)
+	}
+	if len(dst) != len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the output [][]float64 has %d rows, but the product\n"
+		s += "has %d rows. They must match.\n"
+		s = fmt.Sprintf(s, name, len(dst), len(m))
+		panic(s)
+	}
+	for i := range dst {
+		if len(dst[i]) != len(n[0]) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, row %d of the output [][]float64 has %d entries, but\n"
+			s += "the product has %d columns. They must match.\n"
+			s = fmt.Sprintf(s, name, i, len(dst[i]), len(n[0]))
+			panic(s)
+		}
+	}
+}
+
+/*
+Axpy adds a times x to y, element by element, storing the result in y. This
+is the matrix version of vec.Axpy, and is handy for updates such as
+w -= rate * grad:
+
+	mat.Axpy(-rate, grad, w)
+
+Unlike most functions in this package, y is mutated in place. The shape of
+the [][]float64s must be the same (same number of rows, and same number of
+entries in each row). x is not mutated in this function.
+*/
+func Axpy(a float64, x, y [][]float64) {
+	if len(x) != len(y) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%v, the number of the rows of the first slice is %d\n"
+		s += "but the number of rows of the second slice is %d. They must\n"
+		s += "match.\n"
+		s = fmt.Sprintf(s, "Axpy()", len(x), len(y))
+		panic(s)
+	}
+	for i := range x {
+		if len(x[i]) != len(y[i]) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%v, row number %d of the first [][]float64 has length %d,\n"
+			s += "while row number %d of the second [][]float64 has length %d.\n"
+			s += "The length of each row must match.\n"
+			s = fmt.Sprintf(s, "Axpy()", i, len(x[i]), i, len(y[i]))
+			panic(s)
+		}
+	}
+	for i := range y {
+		for j := range y[i] {
+			y[i][j] += a * x[i][j]
+		}
+	}
+}
+
+/*
+Gemm computes alpha times the matrix product of m and n, plus beta times c,
+and stores the result in c. That is, c = alpha*(m·n) + beta*c. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	c := [][]float64{{1.0, 1.0}, {1.0, 1.0}}
+	mat.Gemm(2.0, m, mat.I(2), 1.0, c) // c is now [[3.0, 5.0], [7.0, 9.0]]
+
+With alpha of 1.0 and beta of 0.0 this is the same as mat.DotInto(c, m, n).
+When beta is 0.0, the existing values of c are ignored, even if they are NaN.
+The number of columns of m must equal the number of rows of n, and c must
+have len(m) rows with len(n[0]) entries each. Otherwise, this function will
+panic. c must not share any rows with m or n, and m and n are not mutated
+in this function.
+*/
+func Gemm(alpha float64, m, n [][]float64, beta float64, c [][]float64) {
+	checkDotInto(c, m, n, "Gemm()")
+	for i := range c {
+		for j := range c[i] {
+			if beta == 0.0 {
+				c[i][j] = 0.0
+			} else {
+				c[i][j] *= beta
+			}
+		}
+	}
+	for i := range m {
+		for k := range m[i] {
+			a := alpha * m[i][k]
+			for j := range n[k] {
+				c[i][j] += a * n[k][j]
+			}
+		}
+	}
+}
+
+/*
+ForeachValue calls a given function on each element of a [][]float64, in
+row-major order, without allocating anything. This is useful when the
+elements only need to be read, for example when feeding them to a histogram:
+
+	counts := make(map[float64]int)
+	mat.ForeachValue(m, func(x float64) {
+		counts[x]++
+	})
+
+Unlike mat.Flatten(m), no new []float64 is created. The passed [][]float64
+is not mutated in this function, but the passed function may close over
+other state.
+*/
+func ForeachValue(m [][]float64, f func(float64)) {
+	for i := range m {
+		for j := range m[i] {
+			f(m[i][j])
+		}
+	}
+}
+
+/*
+ApplyRows replaces each row of a [][]float64 with the result of calling a
+given function on that row. This allows functions which act on a []float64,
+such as those in the gocrunch/vec package, to be applied row by row:
+
+	m := [][]float64{{1.0, 3.0}, {2.0, 2.0}}
+	mat.ApplyRows(m, vec.Normalize) // m is now [[0.316..., 0.948...], [0.707..., 0.707...]]
+
+Unlike most functions in this package, m is mutated in place: the values
+returned by f are copied into the existing rows. The function must return a
+[]float64 of the same length as the row it was given, otherwise this
+function will panic.
+*/
+func ApplyRows(m [][]float64, f func(row []float64) []float64) {
+	for i := range m {
+		r := f(m[i])
+		if len(r) != len(m[i]) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, row %d has %d entries, but the passed function\n"
+			s += "returned %d entries for it. They must match.\n"
+			s = fmt.Sprintf(s, "ApplyRows()", i, len(m[i]), len(r))
+			panic(s)
+		}
+		copy(m[i], r)
+	}
+}
+
+/*
+ApplyCols replaces each column of a [][]float64 with the result of calling a
+given function on that column. For example:
+
+	m := [][]float64{{1.0, 2.0}, {3.0, 2.0}}
+	mat.ApplyCols(m, vec.Softmax) // each column of m now sums to 1.0
+
+Each column is passed to f as a new []float64, so f is free to modify it.
+Unlike most functions in this package, m is mutated in place. The function
+must return a []float64 with one element per row, otherwise this function
+will panic. The passed [][]float64 is assumed to be non-jagged.
+*/
+func ApplyCols(m [][]float64, f func(col []float64) []float64) {
+	if len(m) == 0 {
+		return
+	}
+	for j := range m[0] {
+		c := make([]float64, len(m))
+		for i := range m {
+			c[i] = m[i][j]
+		}
+		r := f(c)
+		if len(r) != len(m) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, column %d has %d entries, but the passed function\n"
+			s += "returned %d entries for it. They must match.\n"
+			s = fmt.Sprintf(s, "ApplyCols()", j, len(m), len(r))
+			panic(s)
+		}
+		for i := range m {
+			m[i][j] = r[i]
+		}
+	}
+}
+
+/*
+LogDet returns the natural logarithm of the absolute value of the
+determinant of a square [][]float64, along with the sign of the determinant.
+For example:
+
+	m := [][]float64{{1e-200, 0.0}, {0.0, -1e-200}}
+	logAbsDet, sign := mat.LogDet(m) // logAbsDet is about -921.03, sign is -1.0
+
+The determinant itself is sign * math.Exp(logAbsDet). It is found using an
+LU decomposition with partial pivoting, and the logarithms of the pivots are
+summed rather than multiplying the pivots, so that the result does not
+overflow or underflow for large matrices. If the [][]float64 is singular,
+then -Inf and 0.0 are returned. The passed [][]float64 must be square, and
+it is not mutated in this function.
+*/
+func LogDet(m [][]float64) (float64, float64) {
+	for i := range m {
+		if len(m[i]) != len(m) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the [][]float64 must be square, but it has %d rows,\n"
+			s += "while row %d has %d entries.\n"
+			s = fmt.Sprintf(s, "LogDet()", len(m), i, len(m[i]))
+			panic(s)
+		}
+	}
+	a, _, sign, ok := lu(m)
+	if !ok {
+		return math.Inf(-1), 0.0
+	}
+	logAbsDet := 0.0
+	for i := range a {
+		if a[i][i] < 0.0 {
+			sign = -sign
+		}
+		logAbsDet += math.Log(math.Abs(a[i][i]))
+	}
+	return logAbsDet, sign
+}
+
+/*
+Symmetrize replaces a square [][]float64 with the average of itself and its
+transpose, (m + mᵀ)/2, making it exactly symmetric. For example:
+
+	m := [][]float64{{1.0, 2.0}, {2.000001, 3.0}}
+	mat.Symmetrize(m) // m is now [[1.0, 2.0000005], [2.0000005, 3.0]]
+
+This is useful for cleaning up matrices such as covariances, which should be
+symmetric but may not be exactly so due to rounding, before passing them to
+functions like mat.EigSym. Unlike most functions in this package, m is
+mutated in place. The passed [][]float64 must be square.
+*/
+func Symmetrize(m [][]float64) {
+	for i := range m {
+		if len(m[i]) != len(m) {
+			fmt.Println("\ngocrunch/mat error.")
+			s := "In mat.%s, the [][]float64 must be square, but it has %d rows,\n"
+			s += "while row %d has %d entries.\n"
+			s = fmt.Sprintf(s, "Symmetrize()", len(m), i, len(m[i]))
+			panic(s)
+		}
+	}
+	for i := range m {
+		for j := i + 1; j < len(m); j++ {
+			avg := (m[i][j] + m[j][i]) / 2.0
+			m[i][j] = avg
+			m[j][i] = avg
+		}
+	}
+}
+
+/*
+Try calls the passed function, and returns any panic raised while it runs
+as an error. If f returns normally, nil is returned. This is the recommended
+way to call the functions in this package when a panic is not acceptable,
+such as behind a network boundary:
+
+	var res [][]float64
+	err := mat.Try(func() {
+		res = mat.Dot(m, n)
+	})
+	if err != nil {
+		// m and n could not be multiplied...
+	}
+
+If the panic value is an error, it is returned as is. Otherwise, it is
+formatted into an error, with surrounding whitespace removed. Note that the
+functions in this package still print a short notice to stdout before they
+panic.
+*/
+func Try(f func()) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if e, ok := r.(error); ok {
+			err = e
+			return
+		}
+		err = errors.New(strings.TrimSpace(fmt.Sprint(r)))
+	}()
+	f()
+	return nil
+}
+
+/*
+ElementFunc is a function which maps one element of a [][]float64 to a new
+value, as used by mat.Map.
+*/
+type ElementFunc func(float64) float64
+
+/*
+Map applies a given function to each element of a [][]float64, just like
+mat.Foreach(m, f), with the arguments in the order used by the older numgo
+package:
+
+	n := mat.Map(math.Sqrt, m)
+
+The resultant [][]float64 is returned, leaving the original [][]float64
+intact.
+*/
+func Map(f ElementFunc, m [][]float64) [][]float64 {
+	return Foreach(m, f)
+}
+
+/*
+Ones returns a [][]float64 with r rows and c columns, where all elements are
+1.0. Both dimensions must be greater than 0.
+*/
+func Ones(r, c int) [][]float64 {
+	m, err := NewSafe(r, c)
+	if err != nil {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, %v.\n"
+		s = fmt.Sprintf(s, "Ones()", err)
+		panic(s)
+	}
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = 1.0
+		}
+	}
+	return m
+}
+
+/*
+Inc returns a [][]float64 with r rows and c columns, where the elements are
+increasing integers starting from 0.0, filled one row at a time. For example:
+
+	m := mat.Inc(2, 3) // m is [[0.0, 1.0, 2.0], [3.0, 4.0, 5.0]]
+
+Both dimensions must be greater than 0.
+*/
+func Inc(r, c int) [][]float64 {
+	m, err := NewSafe(r, c)
+	if err != nil {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, %v.\n"
+		s = fmt.Sprintf(s, "Inc()", err)
+		panic(s)
+	}
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*c + j)
+		}
+	}
+	return m
+}
+
+/*
+Reset sets all of the elements of a [][]float64 to 0.0. Unlike mat.Set, the
+passed [][]float64 is mutated in place, and nothing is returned.
+*/
+func Reset(m [][]float64) {
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = 0.0
+		}
+	}
+}
+
+/*
+BootstrapRows returns a new [][]float64 with n rows, each of which is drawn
+uniformly at random, with replacement, from the rows of m. For example:
+
+	r := rand.New(rand.NewSource(42))
+	b := mat.BootstrapRows(m, len(m), r) // a bootstrap sample of m
+
+The same row of m may appear many times in the result, while others may not
+appear at all. Each row of the result is a copy. If r is nil, the default
+source of the math/rand package is used. n cannot be negative, and m cannot
+be empty unless n is 0. The passed [][]float64 is not mutated in this
+function.
+*/
+func BootstrapRows(m [][]float64, n int, r *rand.Rand) [][]float64 {
+	if n < 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows cannot be negative, but received %d.\n"
+		s = fmt.Sprintf(s, "BootstrapRows()", n)
+		panic(s)
+	}
+	if n > 0 && len(m) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, cannot draw %d rows from an empty [][]float64.\n"
+		s = fmt.Sprintf(s, "BootstrapRows()", n)
+		panic(s)
+	}
+	b := make([][]float64, n)
+	for i := range b {
+		row := m[intn(r, len(m))]
+		b[i] = make([]float64, len(row))
+		copy(b[i], row)
+	}
+	return b
+}
+
+// intn returns a random int in [0, n) from r, or from the default source of
+// the math/rand package if r is nil.
+func intn(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	return r.Intn(n)
+}
+
+/*
+KFold splits the rows of a [][]float64 into k folds for cross-validation.
+For each fold f, testFolds[f] holds the rows of that fold, and trainFolds[f]
+holds all of the other rows. For example:
+
+	r := rand.New(rand.NewSource(42))
+	train, test := mat.KFold(m, 5, r)
+	for f := range test {
+		// fit on train[f], evaluate on test[f]...
+	}
+
+The rows are assigned to folds at random, and the sizes of the folds differ
+by at most one row. Every row of m appears in exactly one test fold. Within
+each fold the rows keep their original order, and each row is a copy. If r
+is nil, the default source of the math/rand package is used. k must be at
+least 2, and cannot be larger than the number of rows. The passed
+[][]float64 is not mutated in this function.
+*/
+func KFold(m [][]float64, k int, r *rand.Rand) ([][][]float64, [][][]float64) {
+	if k < 2 || k > len(m) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of folds must be in [2, %d], but received %d.\n"
+		s = fmt.Sprintf(s, "KFold()", len(m), k)
+		panic(s)
+	}
+	fold := make([]int, len(m))
+	for i, p := range perm(r, len(m)) {
+		fold[p] = i * k / len(m)
+	}
+	train := make([][][]float64, k)
+	test := make([][][]float64, k)
+	for f := 0; f < k; f++ {
+		for i := range m {
+			row := make([]float64, len(m[i]))
+			copy(row, m[i])
+			if fold[i] == f {
+				test[f] = append(test[f], row)
+			} else {
+				train[f] = append(train[f], row)
+			}
+		}
+	}
+	return train, test
+}
+
+// perm returns a random permutation of [0, n) from r, or from the default
+// source of the math/rand package if r is nil.
+func perm(r *rand.Rand, n int) []int {
+	if r == nil {
+		return rand.Perm(n)
+	}
+	return r.Perm(n)
+}
+
+/*
+TrainTestSplit shuffles the rows of a [][]float64, and splits them into a
+training set and a test set, where the test set holds the passed fraction of
+the rows. For example:
+
+	r := rand.New(rand.NewSource(42))
+	train, test := mat.TrainTestSplit(m, 0.2, r) // test has 20% of the rows
+
+The number of test rows is testFraction*len(m), rounded to the nearest
+integer. Both sets are in shuffled order, and each row is a copy. If r is
+nil, the default source of the math/rand package is used. The testFraction
+must be in (0, 1). The passed [][]float64 is not mutated in this function.
+To split features and labels with the same shuffle, use
+mat.TrainTestSplitXY.
+*/
+func TrainTestSplit(m [][]float64, testFraction float64, r *rand.Rand) ([][]float64, [][]float64) {
+	checkTestFraction(testFraction, "TrainTestSplit()")
+	p := perm(r, len(m))
+	nTest := int(testFraction*float64(len(m)) + 0.5)
+	return pickRows(m, p[nTest:]), pickRows(m, p[:nTest])
+}
+
+/*
+TrainTestSplitXY splits two [][]float64s, usually the features x and the
+labels y, into training and test sets, just like mat.TrainTestSplit, using
+the same shuffle for both. This means that row i of xTrain corresponds to
+row i of yTrain, and likewise for the test sets:
+
+	xTrain, xTest, yTrain, yTest := mat.TrainTestSplitXY(x, y, 0.2, r)
+
+The two [][]float64s must have the same number of rows, and the testFraction
+must be in (0, 1). The passed [][]float64s are not mutated in this function.
+*/
+func TrainTestSplitXY(x, y [][]float64, testFraction float64, r *rand.Rand) ([][]float64, [][]float64, [][]float64, [][]float64) {
+	checkTestFraction(testFraction, "TrainTestSplitXY()")
+	if len(x) != len(y) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of the first argument is %d,\n"
+		s += "while the number of rows of the second argument is %d. They must match.\n"
+		s = fmt.Sprintf(s, "TrainTestSplitXY()", len(x), len(y))
+		panic(s)
+	}
+	p := perm(r, len(x))
+	nTest := int(testFraction*float64(len(x)) + 0.5)
+	return pickRows(x, p[nTest:]), pickRows(x, p[:nTest]), pickRows(y, p[nTest:]), pickRows(y, p[:nTest])
+}
+
+// checkTestFraction panics on behalf of the named function if the passed
+// fraction is not in (0, 1).
+func checkTestFraction(f float64, name string) {
+	if !(f > 0.0 && f < 1.0) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the test fraction must be in (0, 1), but received %f.\n"
+		s = fmt.Sprintf(s, name, f)
+		panic(s)
+	}
+}
+
+// pickRows returns copies of the rows of m at the passed indices, in order.
+func pickRows(m [][]float64, idx []int) [][]float64 {
+	n := make([][]float64, len(idx))
+	for i, k := range idx {
+		n[i] = make([]float64, len(m[k]))
+		copy(n[i], m[k])
+	}
+	return n
+}
+
+/*
+ConfusionMatrix returns a numClasses by numClasses [][]float64, where the
+element at [i][j] counts the samples whose actual class is i, and whose
+predicted class is j. For example:
+
+	predicted := []int{0, 1, 1, 2}
+	actual := []int{0, 1, 2, 2}
+	c := mat.ConfusionMatrix(predicted, actual, 3) // c is [[1, 0, 0], [0, 1, 0], [0, 1, 1]]
+
+The diagonal holds the correct predictions. The two []ints must have the
+same length, numClasses must be greater than 0, and every label must be in
+[0, numClasses). The passed []ints are not mutated in this function.
+*/
+func ConfusionMatrix(predicted, actual []int, numClasses int) [][]float64 {
+	checkLabels(predicted, actual, numClasses, "ConfusionMatrix()")
+	c, _ := NewSafe(numClasses, numClasses)
+	for i := range actual {
+		c[actual[i]][predicted[i]]++
+	}
+	return c
+}
+
+// checkLabels panics on behalf of the named function if the predicted and
+// actual labels differ in length, or if any label is not in
+// [0, numClasses).
+func checkLabels(predicted, actual []int, numClasses int, name string) {
+	if numClasses <= 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of classes must be greater than 0, but received %d.\n"
+		s = fmt.Sprintf(s, name, numClasses)
+		panic(s)
+	}
+	if len(predicted) != len(actual) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of predicted labels is %d, while the number\n"
+		s += "of actual labels is %d. They must match.\n"
+		s = fmt.Sprintf(s, name, len(predicted), len(actual))
+		panic(s)
+	}
+	for i := range actual {
+		for _, l := range []int{predicted[i], actual[i]} {
+			if l < 0 || l >= numClasses {
+				fmt.Println("\ngocrunch/mat error.")
+				s := "In mat.%s, the label %d at index %d is outside of range [0, %d).\n"
+				s = fmt.Sprintf(s, name, l, i, numClasses)
+				panic(s)
+			}
+		}
+	}
+}
+
+/*
+Accuracy returns the fraction of the predicted labels which are equal to the
+actual labels. For example:
+
+	a := mat.Accuracy([]int{0, 1, 1, 2}, []int{0, 1, 2, 2}) // a is 0.75
+
+The two []ints must have the same length, and cannot be empty. The passed
+[]ints are not mutated in this function.
+*/
+func Accuracy(predicted, actual []int) float64 {
+	if len(predicted) != len(actual) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of predicted labels is %d, while the number\n"
+		s += "of actual labels is %d. They must match.\n"
+		s = fmt.Sprintf(s, "Accuracy()", len(predicted), len(actual))
+		panic(s)
+	}
+	if len(actual) == 0 {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the labels cannot be empty.\n"
+		s = fmt.Sprintf(s, "Accuracy()")
+		panic(s)
+	}
+	correct := 0
+	for i := range actual {
+		if predicted[i] == actual[i] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(actual))
+}
+
+/*
+PrecisionRecallF1 returns the precision, recall, and F1 score of each class,
+as []float64s with numClasses elements. For class c, the precision is the
+fraction of the samples predicted as c which are actually c, the recall is
+the fraction of the samples which are actually c that were predicted as c,
+and the F1 score is the harmonic mean of the two. For example:
+
+	predicted := []int{0, 1, 1, 2}
+	actual := []int{0, 1, 2, 2}
+	p, r, f1 := mat.PrecisionRecallF1(predicted, actual, 3)
+	// p is [1.0, 0.5, 1.0], r is [1.0, 1.0, 0.5], f1 is [1.0, 0.667, 0.667]
+
+When a ratio is undefined, for example the precision of a class which was
+never predicted, it is set to 0.0. The macro-averaged scores, which weigh
+every class equally, are the plain averages of the returned []float64s, as
+given by vec.Avg. These are computed from mat.ConfusionMatrix, and the same
+requirements on the arguments apply. The passed []ints are not mutated in
+this function.
+*/
+func PrecisionRecallF1(predicted, actual []int, numClasses int) ([]float64, []float64, []float64) {
+	checkLabels(predicted, actual, numClasses, "PrecisionRecallF1()")
+	c := ConfusionMatrix(predicted, actual, numClasses)
+	precision := make([]float64, numClasses)
+	recall := make([]float64, numClasses)
+	f1 := make([]float64, numClasses)
+	for k := range c {
+		predictedK, actualK := 0.0, 0.0
+		for i := range c {
+			predictedK += c[i][k]
+			actualK += c[k][i]
+		}
+		if predictedK > 0.0 {
+			precision[k] = c[k][k] / predictedK
+		}
+		if actualK > 0.0 {
+			recall[k] = c[k][k] / actualK
+		}
+		if precision[k]+recall[k] > 0.0 {
+			f1[k] = 2.0 * precision[k] * recall[k] / (precision[k] + recall[k])
+		}
+	}
+	return precision, recall, f1
+}
+
+/*
+LinReg fits an ordinary least squares linear regression of y on the columns
+of x, and returns the coefficients. Each row of x is a sample, and each
+column is a feature. If intercept is true, a column of 1.0s is prepended to
+x, and the first coefficient is the intercept. For example:
+
+	x := [][]float64{{1.0}, {2.0}, {3.0}}
+	y := []float64{3.0, 5.0, 7.0}
+	b := mat.LinReg(x, y, true) // b is [1.0, 2.0], meaning y = 1.0 + 2.0*x
+
+The coefficients b solve the normal equations (XᵀX)b = Xᵀy using an LU
+decomposition. This is fast, but squares the condition number of X, so it
+is best suited to well conditioned problems. The length of y must equal the
+number of rows of x, and x is assumed to be non-jagged. If XᵀX is singular,
+for example because some columns of x are collinear or there are fewer
+samples than coefficients, this function will panic; mat.Rank can be used to
+find such problems beforehand. XᵀX is also treated as singular when a pivot
+of its LU decomposition is at most n times the machine epsilon times its
+largest absolute element, where n is the number of coefficients, since
+nearly collinear columns would otherwise give huge, meaningless
+coefficients. The passed arguments are not mutated in this
+function.
+*/
+func LinReg(x [][]float64, y []float64, intercept bool) []float64 {
+	if len(x) != len(y) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, the number of rows of x is %d, while the length of y\n"
+		s += "is %d. They must match.\n"
+		s = fmt.Sprintf(s, "LinReg()", len(x), len(y))
+		panic(s)
+	}
+	a := x
+	if intercept {
+		a = make([][]float64, len(x))
+		for i := range x {
+			a[i] = make([]float64, len(x[i])+1)
+			a[i][0] = 1.0
+			copy(a[i][1:], x[i])
+		}
+	}
+	at := T(a)
+	xtx := Dot(at, a)
+	f, p, _, ok := lu(xtx)
+	if !ok || tinyPivot(xtx, f) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, XᵀX is singular, so the normal equations have no unique\n"
+		s += "solution. Check for collinear columns (mat.Rank(x, 0.0) is %d for\n"
+		s += "%d coefficients), or for fewer samples than coefficients.\n"
+		s = fmt.Sprintf(s, "LinReg()", Rank(a, 0.0), len(xtx))
+		panic(s)
+	}
+	b := make([]float64, len(at))
+	for i := range b {
+		row := at[p[i]]
+		for k := range row {
+			b[i] += row[k] * y[k]
+		}
+	}
+	luSolve(f, b)
+	return b
+}
+
+/*
+Sigmoid returns a new [][]float64 where the logistic function,
+1 / (1 + e^-x), has been applied to each element. For example:
+
+	m := mat.Sigmoid([][]float64{{-1000.0, 0.0}, {1000.0, 0.0}}) // m is [[0.0, 0.5], [1.0, 0.5]]
+
+The function is evaluated in a form which does not overflow for large
+negative or positive inputs. The original [][]float64 is not mutated in this
+function.
+*/
+func Sigmoid(m [][]float64) [][]float64 {
+	return Foreach(m, sigmoid)
+}
+
+/*
+SigmoidPrime returns a new [][]float64 where the derivative of the logistic
+function, s * (1 - s) with s = 1 / (1 + e^-x), has been applied to each
+element. For example:
+
+	m := mat.SigmoidPrime([][]float64{{-1000.0, 0.0}, {1000.0, 0.0}}) // m is [[0.0, 0.25], [0.0, 0.25]]
+
+The logistic function is evaluated in the same overflow safe form as in
+mat.Sigmoid. The original [][]float64 is not mutated in this function.
+*/
+func SigmoidPrime(m [][]float64) [][]float64 {
+	return Foreach(m, func(x float64) float64 {
+		s := sigmoid(x)
+		return s * (1.0 - s)
+	})
+}
+
+// sigmoid returns the logistic function of x. For negative x it uses the
+// form e^x / (1 + e^x), so that e^-x never overflows.
+func sigmoid(x float64) float64 {
+	if x >= 0.0 {
+		return 1.0 / (1.0 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1.0 + e)
+}
+
+/*
+Tanh returns a new [][]float64 where the hyperbolic tangent has been applied
+to each element. For example:
+
+	m := mat.Tanh([][]float64{{-1000.0, 0.0}, {1000.0, 0.0}}) // m is [[-1.0, 0.0], [1.0, 0.0]]
+
+The original [][]float64 is not mutated in this function.
+*/
+func Tanh(m [][]float64) [][]float64 {
+	return Foreach(m, math.Tanh)
+}
+
+/*
+ReLU returns a new [][]float64 where each negative element has been replaced
+by 0.0, which is the rectified linear unit, max(0, x). For example:
+
+	m := mat.ReLU([][]float64{{-2.0, 0.0}, {3.0, -0.5}}) // m is [[0.0, 0.0], [3.0, 0.0]]
+
+NaN elements are left as NaN. The original [][]float64 is not mutated in this
+function.
+*/
+func ReLU(m [][]float64) [][]float64 {
+	return Foreach(m, relu)
+}
+
+// relu returns x if it is not negative, and 0.0 otherwise.
+func relu(x float64) float64 {
+	if x < 0.0 {
+		return 0.0
+	}
+	return x
+}
+
+/*
+Exp returns a new [][]float64 where e^x has been applied to each element.
+The original [][]float64 is not mutated in this function.
+*/
+func Exp(m [][]float64) [][]float64 {
+	return Foreach(m, math.Exp)
+}
+
+/*
+Log returns a new [][]float64 where the natural logarithm has been applied
+to each element. Like math.Log, the logarithm of 0.0 is -Inf, and the
+logarithm of a negative number is NaN; use mat.LogStrict to panic instead.
+The original [][]float64 is not mutated in this function.
+*/
+func Log(m [][]float64) [][]float64 {
+	return Foreach(m, math.Log)
+}
+
+/*
+LogStrict returns a new [][]float64 where the natural logarithm has been
+applied to each element, just like mat.Log, but panics if any element is
+not greater than 0.0. The original [][]float64 is not mutated in this
+function.
+*/
+func LogStrict(m [][]float64) [][]float64 {
+	for i := range m {
+		for j := range m[i] {
+			if !(m[i][j] > 0.0) {
+				fmt.Println("\ngocrunch/mat error.")
+				s := "In mat.%s, the element at [%d][%d] is %f, which must be greater than 0.0.\n"
+				s = fmt.Sprintf(s, "LogStrict()", i, j, m[i][j])
+				panic(s)
+			}
+		}
+	}
+	return Foreach(m, math.Log)
+}
+
+/*
+Sqrt returns a new [][]float64 where the square root has been applied to
+each element. Like math.Sqrt, the square root of a negative number is NaN;
+use mat.SqrtStrict to panic instead. The original [][]float64 is not
+mutated in this function.
+*/
+func Sqrt(m [][]float64) [][]float64 {
+	return Foreach(m, math.Sqrt)
+}
+
+/*
+SqrtStrict returns a new [][]float64 where the square root has been applied
+to each element, just like mat.Sqrt, but panics if any element is negative
+or NaN. The original [][]float64 is not mutated in this function.
+*/
+func SqrtStrict(m [][]float64) [][]float64 {
+	for i := range m {
+		for j := range m[i] {
+			if !(m[i][j] >= 0.0) {
+				fmt.Println("\ngocrunch/mat error.")
+				s := "In mat.%s, the element at [%d][%d] is %f, which must be at least 0.0.\n"
+				s = fmt.Sprintf(s, "SqrtStrict()", i, j, m[i][j])
+				panic(s)
+			}
+		}
+	}
+	return Foreach(m, math.Sqrt)
+}
+
+/*
+LogClamped returns a new [][]float64 where each element x has been replaced
+by log(max(x, eps)). For example:
+
+	m := mat.LogClamped([][]float64{{0.0, 1.0}}, 1e-12) // m is [[log(1e-12), 0.0]]
+
+This avoids the -Inf and NaN that a plain logarithm gives for zero and
+negative values, which is useful for losses such as the cross-entropy.
+NaN elements are left as NaN. eps must be greater than 0.0. The original
+[][]float64 is not mutated in this function.
+*/
+func LogClamped(m [][]float64, eps float64) [][]float64 {
+	if !(eps > 0.0) {
+		fmt.Println("\ngocrunch/mat error.")
+		s := "In mat.%s, eps must be greater than 0.0, but received %f.\n"
+		s = fmt.Sprintf(s, "LogClamped()", eps)
+		panic(s)
+	}
+	return Foreach(m, func(x float64) float64 {
+		if x < eps {
+			x = eps
+		}
+		return math.Log(x)
+	})
+}
@@ -1,8 +1,12 @@
 package mat
 
 import (
+	"fmt"
 	"log"
+	"math"
+	"math/rand"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -29,6 +33,36 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewPanics(t *testing.T) {
+	for _, dims := range [][]int{{0}, {-3}, {2, 0}, {0, 2}} {
+		err := Try(func() {
+			New(dims...)
+		})
+		if err == nil || !strings.HasPrefix(err.Error(), "In mat.New(), the number of") {
+			t.Errorf("expected a mat.New() error for dimensions %v, got %v", dims, err)
+		}
+	}
+}
+
+func TestNewSafe(t *testing.T) {
+	m, err := NewSafe(3, 4)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if !Equal(m, New(3, 4)) {
+		t.Errorf("expected %v, got %v", New(3, 4), m)
+	}
+	for _, dims := range [][]int{{0, 4}, {3, -1}, {-2, -2}} {
+		m, err = NewSafe(dims[0], dims[1])
+		if err == nil {
+			t.Errorf("expected an error for dimensions %v", dims)
+		}
+		if m != nil {
+			t.Errorf("expected a nil [][]float64 for dimensions %v, got %v", dims, m)
+		}
+	}
+}
+
 func TestI(t *testing.T) {
 	row := 10
 	m := I(row)
@@ -95,6 +129,57 @@ func TestFlatten(t *testing.T) {
 	}
 }
 
+func TestFromCSVJagged(t *testing.T) {
+	filename := "jagged_test.csv"
+	f, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f.Write([]byte("1.0,2.0\n3.0,4.0\n5.0\n"))
+	f.Close()
+	defer os.Remove(filename)
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("expected a panic for a jagged file")
+		}
+		s := fmt.Sprint(r)
+		e := "In mat.FromCSV(), line 3 in jagged_test.csv has 1 entries. The first line\n"
+		e += "(line 1) has 2 entries.\n"
+		if !strings.HasPrefix(s, e) {
+			t.Errorf("expected the panic to start with %q, got %q", e, s)
+		}
+	}()
+	FromCSV(filename)
+}
+
+func TestCSVShape(t *testing.T) {
+	filename := "csvshape_test.csv"
+	ToCSV(New(7, 3), filename)
+	r, c, err := CSVShape(filename)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if r != 7 || c != 3 {
+		t.Errorf("expected a shape of 7 by 3, got %d by %d", r, c)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f.Write([]byte("1.0,2.0\n3.0,4.0\n5.0\n"))
+	f.Close()
+	_, _, err = CSVShape(filename)
+	if err == nil {
+		t.Errorf("expected an error for a jagged file")
+	}
+	os.Remove(filename)
+	_, _, err = CSVShape(filename)
+	if err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
+
 func TestToCSV(t *testing.T) {
 	m := New(23, 17)
 	filename := "tocsv_test.csv"
@@ -109,6 +194,39 @@ func TestToCSV(t *testing.T) {
 	os.Remove(filename)
 }
 
+func TestAppendCSV(t *testing.T) {
+	filename := "appendcsv_test.csv"
+	os.Remove(filename)
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	if err := AppendCSV(m, filename); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := AppendCSV([][]float64{{5.0, 6.0}}, filename); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	n := FromCSV(filename)
+	if !Equal(n, [][]float64{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}) {
+		t.Errorf("expected [[1.0, 2.0], [3.0, 4.0], [5.0, 6.0]], got %v", n)
+	}
+	if err := AppendCSV([][]float64{{7.0, 8.0, 9.0}}, filename); err == nil {
+		t.Errorf("expected an error for a row of the wrong width")
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f.Write([]byte("\n"))
+	f.Close()
+	if err := AppendCSV([][]float64{{7.0, 8.0}}, filename); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	n = FromCSV(filename)
+	if len(n) != 4 || n[3][1] != 8.0 {
+		t.Errorf("expected the row [7.0, 8.0] to be appended, got %v", n)
+	}
+	os.Remove(filename)
+}
+
 func TestForeach(t *testing.T) {
 	rows := 132
 	cols := 24
@@ -410,6 +528,14 @@ func TestRand(t *testing.T) {
 			}
 		}
 	}
+	m = Rand(row, col, -5.0)
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] <= -5.0 || m[i][j] > 0.0 {
+				t.Errorf("at index %d, expected (-5.0, 0.0], got %f", i, m[i][j])
+			}
+		}
+	}
 	m = Rand(row, col, -12.0, 2.0)
 	for i := range m {
 		for j := range m[i] {
@@ -706,6 +832,79 @@ func TestAvg(t *testing.T) {
 	if a != val {
 		t.Errorf("expected %f, got %f", val, a)
 	}
+	m = Inc(3, 4)
+	for i, e := range []float64{1.5, 5.5, 9.5} {
+		if a = Avg(m, 0, i); a != e {
+			t.Errorf("at row %d expected %f, got %f", i, e, a)
+		}
+		if a = Avg(m, 0, i-3); a != e {
+			t.Errorf("at row %d expected %f, got %f", i-3, e, a)
+		}
+	}
+	for j, e := range []float64{4.0, 5.0, 6.0, 7.0} {
+		if a = Avg(m, 1, j); a != e {
+			t.Errorf("at col %d expected %f, got %f", j, e, a)
+		}
+		if a = Avg(m, 1, j-4); a != e {
+			t.Errorf("at col %d expected %f, got %f", j-4, e, a)
+		}
+	}
+	defer func() {
+		r := recover()
+		e := "In mat.Avg() the requested row 3 is outside of bounds [-3, 3)\n"
+		if r != e {
+			t.Errorf("expected panic %q, got %v", e, r)
+		}
+	}()
+	Avg(m, 0, 3)
+}
+
+func TestAvgColBounds(t *testing.T) {
+	defer func() {
+		r := recover()
+		e := "In mat.Avg() the requested column -5 is outside of bounds [-4, 4)\n"
+		if r != e {
+			t.Errorf("expected panic %q, got %v", e, r)
+		}
+	}()
+	Avg(Inc(3, 4), 1, -5)
+}
+
+func TestSumProdAxes(t *testing.T) {
+	m := Inc(3, 4)
+	for i, e := range []float64{6.0, 22.0, 38.0} {
+		if q := Sum(m, 0, i); q != e {
+			t.Errorf("at row %d expected sum to be %f, got %f", i, e, q)
+		}
+		if q := Sum(m, 0, i-3); q != e {
+			t.Errorf("at row %d expected sum to be %f, got %f", i-3, e, q)
+		}
+	}
+	for j, e := range []float64{12.0, 15.0, 18.0, 21.0} {
+		if q := Sum(m, 1, j); q != e {
+			t.Errorf("at col %d expected sum to be %f, got %f", j, e, q)
+		}
+		if q := Sum(m, 1, j-4); q != e {
+			t.Errorf("at col %d expected sum to be %f, got %f", j-4, e, q)
+		}
+	}
+	m = Add(m, 1.0)
+	for i, e := range []float64{24.0, 1680.0, 11880.0} {
+		if q := Prod(m, 0, i); q != e {
+			t.Errorf("at row %d expected prod to be %f, got %f", i, e, q)
+		}
+		if q := Prod(m, 0, i-3); q != e {
+			t.Errorf("at row %d expected prod to be %f, got %f", i-3, e, q)
+		}
+	}
+	for j, e := range []float64{45.0, 120.0, 231.0, 384.0} {
+		if q := Prod(m, 1, j); q != e {
+			t.Errorf("at col %d expected prod to be %f, got %f", j, e, q)
+		}
+		if q := Prod(m, 1, j-4); q != e {
+			t.Errorf("at col %d expected prod to be %f, got %f", j-4, e, q)
+		}
+	}
 }
 
 func TestDot(t *testing.T) {
@@ -751,4 +950,1559 @@ func TestAppendCol(t *testing.T) {
 			t.Errorf("expected length of 6, got %d", len(m))
 		}
 	}
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("expected a panic for a mismatched length")
+		}
+		s := fmt.Sprint(r)
+		if !strings.HasPrefix(s, "In mat.AppendCol(), the number of rows of the first argument is 10,") {
+			t.Errorf("unexpected panic message %q", s)
+		}
+	}()
+	AppendCol(m, make([]float64, 3))
+}
+
+func TestAppendRow(t *testing.T) {
+	m := New(2, 2)
+	v := []float64{1.0, 2.0}
+	n := AppendRow(m, v)
+	if !Equal(n, [][]float64{{0.0, 0.0}, {0.0, 0.0}, {1.0, 2.0}}) {
+		t.Errorf("expected [[0.0, 0.0], [0.0, 0.0], [1.0, 2.0]], got %v", n)
+	}
+	if len(m) != 2 {
+		t.Errorf("the [][]float64 was mutated: %v", m)
+	}
+	v[0] = 5.0
+	if n[2][0] != 1.0 {
+		t.Errorf("expected the new row to be a copy of the []float64")
+	}
+	n = AppendRow(nil, v)
+	if !Equal(n, [][]float64{{5.0, 2.0}}) {
+		t.Errorf("expected [[5.0, 2.0]], got %v", n)
+	}
+	v[1] = 7.0
+	if n[0][1] != 2.0 {
+		t.Errorf("expected the only row to be a copy of the []float64")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a mismatched length")
+		}
+	}()
+	AppendRow(m, make([]float64, 3))
+}
+
+func TestConcat(t *testing.T) {
+	m := [][]float64{{1.0}, {2.0}}
+	n := [][]float64{{3.0, 4.0}, {5.0, 6.0}}
+	o := Concat(m, n)
+	if !Equal(o, [][]float64{{1.0, 3.0, 4.0}, {2.0, 5.0, 6.0}}) {
+		t.Errorf("expected [[1.0, 3.0, 4.0], [2.0, 5.0, 6.0]], got %v", o)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for mismatched rows")
+		}
+	}()
+	Concat(m, New(3, 1))
+}
+
+func TestConcatDoesNotMutate(t *testing.T) {
+	// Give the rows of m spare capacity, so that appending to them would
+	// silently write into their backing arrays.
+	m := make([][]float64, 2)
+	for i := range m {
+		m[i] = make([]float64, 2, 10)
+		m[i][0], m[i][1] = float64(i), float64(i)+0.5
+	}
+	n := [][]float64{{7.0, 8.0}, {9.0, 10.0}}
+	o := Concat(m, n)
+	if !Equal(o, [][]float64{{0.0, 0.5, 7.0, 8.0}, {1.0, 1.5, 9.0, 10.0}}) {
+		t.Errorf("expected [[0.0, 0.5, 7.0, 8.0], [1.0, 1.5, 9.0, 10.0]], got %v", o)
+	}
+	if !Equal(m, [][]float64{{0.0, 0.5}, {1.0, 1.5}}) {
+		t.Errorf("the first [][]float64 was mutated: %v", m)
+	}
+	if !Equal(n, [][]float64{{7.0, 8.0}, {9.0, 10.0}}) {
+		t.Errorf("the second [][]float64 was mutated: %v", n)
+	}
+	o[0][0] = 100.0
+	o[0][2] = 100.0
+	if m[0][0] != 0.0 || n[0][0] != 7.0 {
+		t.Errorf("expected the result not to share memory with the arguments")
+	}
+	if ext := m[0][:3]; ext[2] != 0.0 {
+		t.Errorf("expected the spare capacity of m to be untouched, got %v", ext)
+	}
+}
+
+func TestVStack(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}}
+	n := [][]float64{{3.0, 4.0}, {5.0, 6.0}}
+	o := VStack(m, n)
+	if !Equal(o, [][]float64{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}) {
+		t.Errorf("expected [[1.0, 2.0], [3.0, 4.0], [5.0, 6.0]], got %v", o)
+	}
+	o[0][0] = 10.0
+	if m[0][0] != 1.0 {
+		t.Errorf("expected the rows of the result to be copies")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for mismatched columns")
+		}
+	}()
+	VStack(m, New(1, 3))
+}
+
+func TestConvolve2D(t *testing.T) {
+	m := [][]float64{
+		{1.0, 2.0, 3.0},
+		{4.0, 5.0, 6.0},
+		{7.0, 8.0, 9.0},
+	}
+	k := [][]float64{
+		{1.0, 0.0},
+		{0.0, 0.0},
+	}
+	o := Convolve2D(m, k, "full")
+	if len(o) != 4 || len(o[0]) != 4 {
+		t.Errorf("expected 4 by 4, got %d by %d", len(o), len(o[0]))
+	}
+	if !Equal(o[:3], [][]float64{
+		{1.0, 2.0, 3.0, 0.0},
+		{4.0, 5.0, 6.0, 0.0},
+		{7.0, 8.0, 9.0, 0.0},
+	}) {
+		t.Errorf("unexpected full convolution: %v", o)
+	}
+	o = Convolve2D(m, k, "valid")
+	if !Equal(o, [][]float64{{5.0, 6.0}, {8.0, 9.0}}) {
+		t.Errorf("expected [[5.0, 6.0], [8.0, 9.0]], got %v", o)
+	}
+	o = Correlate2D(m, k, "valid")
+	if !Equal(o, [][]float64{{1.0, 2.0}, {4.0, 5.0}}) {
+		t.Errorf("expected [[1.0, 2.0], [4.0, 5.0]], got %v", o)
+	}
+	k = [][]float64{
+		{0.0, 0.0, 0.0},
+		{0.0, 1.0, 0.0},
+		{0.0, 0.0, 0.0},
+	}
+	o = Convolve2D(m, k, "same")
+	if !Equal(o, m) {
+		t.Errorf("expected %v, got %v", m, o)
+	}
+	o = Correlate2D(m, k, "same")
+	if !Equal(o, m) {
+		t.Errorf("expected %v, got %v", m, o)
+	}
+}
+
+func TestPad(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := Pad(m, 1, 0, 0, 2, 9.0)
+	expected := [][]float64{
+		{9.0, 9.0, 9.0, 9.0},
+		{1.0, 2.0, 9.0, 9.0},
+		{3.0, 4.0, 9.0, 9.0},
+	}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Pad(m, 0, 0, 0, 0, 9.0)
+	if !Equal(n, m) {
+		t.Errorf("expected %v, got %v", m, n)
+	}
+}
+
+func TestTile(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := Tile(m, 2, 3)
+	if len(n) != 4 {
+		t.Errorf("expected 4 rows, got %d", len(n))
+	}
+	for i := range n {
+		if len(n[i]) != 6 {
+			t.Errorf("at row %d, expected 6 columns, got %d", i, len(n[i]))
+		}
+		for j := range n[i] {
+			if n[i][j] != m[i%2][j%2] {
+				t.Errorf("at [%d][%d], expected %f, got %f", i, j, m[i%2][j%2], n[i][j])
+			}
+		}
+	}
+}
+
+func TestRot90(t *testing.T) {
+	m := [][]float64{{1.0, 2.0, 3.0}, {4.0, 5.0, 6.0}}
+	n := Rot90(m, 1)
+	expected := [][]float64{{3.0, 6.0}, {2.0, 5.0}, {1.0, 4.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Rot90(m, -1)
+	expected = [][]float64{{4.0, 1.0}, {5.0, 2.0}, {6.0, 3.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Rot90(m, 2)
+	expected = [][]float64{{6.0, 5.0, 4.0}, {3.0, 2.0, 1.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Rot90(m, 4)
+	if !Equal(n, m) {
+		t.Errorf("expected %v, got %v", m, n)
+	}
+	n[0][0] = 100.0
+	if m[0][0] == 100.0 {
+		t.Errorf("Rot90 with k of 4 did not return a copy")
+	}
+}
+
+func TestFlipV(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}
+	n := FlipV(m)
+	expected := [][]float64{{5.0, 6.0}, {3.0, 4.0}, {1.0, 2.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestFlipH(t *testing.T) {
+	m := [][]float64{{1.0, 2.0, 3.0}, {4.0, 5.0, 6.0}}
+	n := FlipH(m)
+	expected := [][]float64{{3.0, 2.0, 1.0}, {6.0, 5.0, 4.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestAddCol(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := AddCol(m, []float64{10.0, 20.0})
+	expected := [][]float64{{11.0, 12.0}, {23.0, 24.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestSubCol(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := SubCol(m, []float64{1.0, 3.0})
+	expected := [][]float64{{0.0, 1.0}, {0.0, 1.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestMulCol(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := MulCol(m, []float64{2.0, 10.0})
+	expected := [][]float64{{2.0, 4.0}, {30.0, 40.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestDivCol(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := DivCol(m, []float64{1.0, 2.0})
+	expected := [][]float64{{1.0, 2.0}, {1.5, 2.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestRSub(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := RSub(m, 1.0)
+	expected := [][]float64{{0.0, -1.0}, {-2.0, -3.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestRDiv(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {4.0, 8.0}}
+	n := RDiv(m, 1.0)
+	expected := [][]float64{{1.0, 0.5}, {0.25, 0.125}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestCumSum(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := CumSum(m, 0)
+	expected := [][]float64{{1.0, 2.0}, {4.0, 6.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = CumSum(m, 1)
+	expected = [][]float64{{1.0, 3.0}, {3.0, 7.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestForeachParallel(t *testing.T) {
+	m := Rand(133, 24)
+	f := func(i float64) float64 {
+		return i * 2.0
+	}
+	for _, workers := range []int{0, 1, 5} {
+		n := ForeachParallel(f, m, workers)
+		if !Equal(n, Foreach(m, f)) {
+			t.Errorf("with %d workers, expected the same result as Foreach", workers)
+		}
+	}
+}
+
+func BenchmarkForeachExpensive(b *testing.B) {
+	m := Rand(300, 1000)
+	f := func(i float64) float64 {
+		return math.Sin(3.0*i*i*i - 2.0*i*i + i - 7.0)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Foreach(m, f)
+	}
+}
+
+func BenchmarkForeachParallel(b *testing.B) {
+	m := Rand(300, 1000)
+	f := func(i float64) float64 {
+		return math.Sin(3.0*i*i*i - 2.0*i*i + i - 7.0)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ForeachParallel(f, m, 0)
+	}
+}
+
+func TestSumParallel(t *testing.T) {
+	m := New(131, 17)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*17 + j)
+		}
+	}
+	for _, workers := range []int{0, 1, 4, 500} {
+		res := SumParallel(m, workers)
+		if res != Sum(m) {
+			t.Errorf("with %d workers, expected %f, got %f", workers, Sum(m), res)
+		}
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	m := New(1000, 1000)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*1000 + j)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Sum(m)
+	}
+}
+
+func BenchmarkSumParallel(b *testing.B) {
+	m := New(1000, 1000)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*1000 + j)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = SumParallel(m, 0)
+	}
+}
+
+func TestAvgParallel(t *testing.T) {
+	m := New(31, 17)
+	m = Set(m, 3.0)
+	res := AvgParallel(m, 4)
+	if res != 3.0 {
+		t.Errorf("expected 3.0, got %f", res)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	m := New(12, 7)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*7 + j)
+		}
+	}
+	add := func(acc, val float64) float64 {
+		return acc + val
+	}
+	res := Reduce(m, 0.0, add)
+	if res != Sum(m) {
+		t.Errorf("expected %f, got %f", Sum(m), res)
+	}
+	max := func(acc, val float64) float64 {
+		return math.Max(acc, val)
+	}
+	res = Reduce(m, math.Inf(-1), max)
+	if res != 83.0 {
+		t.Errorf("expected 83.0, got %f", res)
+	}
+}
+
+func TestIsDiagonal(t *testing.T) {
+	if !IsDiagonal(I(5), 0.0) {
+		t.Errorf("expected identity to be diagonal")
+	}
+	m := [][]float64{{1.0, 1e-9}, {0.0, 2.0}}
+	if !IsDiagonal(m, 1e-6) {
+		t.Errorf("expected %v to be diagonal within 1e-6", m)
+	}
+	if IsDiagonal(m, 0.0) {
+		t.Errorf("expected %v not to be diagonal within 0.0", m)
+	}
+	if IsDiagonal(New(2, 3), 0.0) {
+		t.Errorf("expected non-square matrix not to be diagonal")
+	}
+}
+
+func TestDotBanded(t *testing.T) {
+	m := New(10)
+	for i := range m {
+		for j := range m[i] {
+			if j >= i-1 && j <= i+1 {
+				m[i][j] = float64(i*10 + j + 1)
+			}
+		}
+	}
+	n := New(10, 7)
+	for i := range n {
+		for j := range n[i] {
+			n[i][j] = float64(i*7 + j)
+		}
+	}
+	o := DotBanded(m, n, 1)
+	if !Equal(o, Dot(m, n)) {
+		t.Errorf("expected DotBanded to match Dot")
+	}
+}
+
+func BenchmarkDotBanded(b *testing.B) {
+	m := New(1000)
+	n := New(1000)
+	for i := range m {
+		for j := range m[i] {
+			if j >= i-1 && j <= i+1 {
+				m[i][j] = float64(i*10 + j)
+			}
+			n[i][j] = 1.0
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = DotBanded(m, n, 1)
+	}
+}
+
+func TestTInPlace(t *testing.T) {
+	m := New(11)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*11 + j)
+		}
+	}
+	n := T(m)
+	TInPlace(m)
+	if !Equal(m, n) {
+		t.Errorf("expected TInPlace to match T")
+	}
+}
+
+func BenchmarkTInPlace(b *testing.B) {
+	m := New(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		TInPlace(m)
+	}
+}
+
+func TestSoftmaxRows(t *testing.T) {
+	m := [][]float64{{1.0, 1.0}, {1000.0, -1000.0}}
+	n := SoftmaxRows(m)
+	expected := [][]float64{{0.5, 0.5}, {1.0, 0.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+}
+
+func TestPairwiseDist(t *testing.T) {
+	m := [][]float64{{0.0, 0.0}, {3.0, 4.0}, {0.0, 1.0}}
+	d := PairwiseDist(m)
+	expected := [][]float64{
+		{0.0, 5.0, 1.0},
+		{5.0, 0.0, math.Sqrt(18.0)},
+		{1.0, math.Sqrt(18.0), 0.0},
+	}
+	if !Equal(d, expected) {
+		t.Errorf("expected %v, got %v", expected, d)
+	}
+}
+
+func TestPairwiseDistFunc(t *testing.T) {
+	m := [][]float64{{0.0, 0.0}, {3.0, 4.0}}
+	manhattan := func(a, b []float64) float64 {
+		sum := 0.0
+		for i := range a {
+			sum += math.Abs(a[i] - b[i])
+		}
+		return sum
+	}
+	d := PairwiseDistFunc(m, manhattan)
+	expected := [][]float64{{0.0, 7.0}, {7.0, 0.0}}
+	if !Equal(d, expected) {
+		t.Errorf("expected %v, got %v", expected, d)
+	}
+}
+
+func TestCov(t *testing.T) {
+	m := [][]float64{
+		{1.0, 2.0, 5.0},
+		{2.0, 4.0, 3.0},
+		{3.0, 6.0, 1.0},
+	}
+	c := Cov(m)
+	expected := [][]float64{
+		{1.0, 2.0, -2.0},
+		{2.0, 4.0, -4.0},
+		{-2.0, -4.0, 4.0},
+	}
+	if !Equal(c, expected) {
+		t.Errorf("expected %v, got %v", expected, c)
+	}
+}
+
+func TestCovPop(t *testing.T) {
+	m := [][]float64{
+		{1.0, 2.0},
+		{3.0, 6.0},
+	}
+	c := CovPop(m)
+	expected := [][]float64{
+		{1.0, 2.0},
+		{2.0, 4.0},
+	}
+	if !Equal(c, expected) {
+		t.Errorf("expected %v, got %v", expected, c)
+	}
+}
+
+func TestCorr(t *testing.T) {
+	m := [][]float64{
+		{1.0, 2.0, 5.0, 7.0},
+		{2.0, 4.0, 3.0, 7.0},
+		{3.0, 6.0, 1.0, 7.0},
+	}
+	c := Corr(m)
+	expected := [][]float64{
+		{1.0, 1.0, -1.0},
+		{1.0, 1.0, -1.0},
+		{-1.0, -1.0, 1.0},
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(c[i][j]-expected[i][j]) > 1e-15 {
+				t.Errorf("at [%d][%d], expected %f, got %f", i, j, expected[i][j], c[i][j])
+			}
+		}
+	}
+	for i := range c {
+		if !math.IsNaN(c[i][3]) || !math.IsNaN(c[3][i]) {
+			t.Errorf("expected NaN for the constant column, got %f and %f", c[i][3], c[3][i])
+		}
+	}
+}
+
+func TestPCA(t *testing.T) {
+	m := [][]float64{
+		{1.0, 1.0},
+		{2.0, 2.0},
+		{3.0, 3.0},
+		{4.0, 4.0},
+	}
+	p, v := PCA(m, 1)
+	if len(p) != 4 || len(p[0]) != 1 {
+		t.Errorf("expected 4 by 1, got %d by %d", len(p), len(p[0]))
+	}
+	if math.Abs(v[0]-10.0/3.0) > 1e-12 {
+		t.Errorf("expected variance %f, got %f", 10.0/3.0, v[0])
+	}
+	for i := range p {
+		expected := (float64(i) - 1.5) * math.Sqrt(2.0)
+		if math.Abs(p[i][0]-expected) > 1e-12 {
+			t.Errorf("at row %d, expected %f, got %f", i, expected, p[i][0])
+		}
+	}
+	p, v = PCA(m, 2)
+	if math.Abs(v[1]) > 1e-12 {
+		t.Errorf("expected the second variance to be 0.0, got %f", v[1])
+	}
+}
+
+func TestEigSym(t *testing.T) {
+	m := [][]float64{
+		{4.0, 1.0, 2.0},
+		{1.0, 3.0, 0.0},
+		{2.0, 0.0, 5.0},
+	}
+	values, vectors := EigSym(m, 100, 1e-12)
+	for i := 1; i < len(values); i++ {
+		if values[i] > values[i-1] {
+			t.Errorf("expected descending eigenvalues, got %v", values)
+		}
+	}
+	for i := range values {
+		v := Col(vectors, i)
+		for j := range m {
+			mv := 0.0
+			for k := range m[j] {
+				mv += m[j][k] * v[k]
+			}
+			if math.Abs(mv-values[i]*v[j]) > 1e-10 {
+				t.Errorf("eigenpair %d does not satisfy m.v = lambda.v", i)
+			}
+		}
+	}
+	if math.Abs(values[0]+values[1]+values[2]-12.0) > 1e-10 {
+		t.Errorf("expected the eigenvalues to sum to the trace, 12.0, got %v", values)
+	}
+}
+
+func TestJacobian(t *testing.T) {
+	f := func(x []float64) []float64 {
+		return []float64{x[0] * x[1], x[0] + x[1], x[0] * x[0]}
+	}
+	j := Jacobian(f, []float64{2.0, 3.0}, 0.0)
+	expected := [][]float64{{3.0, 2.0}, {1.0, 1.0}, {4.0, 0.0}}
+	if len(j) != 3 || len(j[0]) != 2 {
+		t.Errorf("expected 3 by 2, got %d by %d", len(j), len(j[0]))
+	}
+	for r := range expected {
+		for c := range expected[r] {
+			if math.Abs(j[r][c]-expected[r][c]) > 1e-8 {
+				t.Errorf("at [%d][%d], expected %f, got %f", r, c, expected[r][c], j[r][c])
+			}
+		}
+	}
+}
+
+func TestWeightedAvgRows(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 6.0}}
+	w := []float64{3.0, 1.0}
+	v := WeightedAvgRows(m, w)
+	if len(v) != 2 || v[0] != 1.5 || v[1] != 3.0 {
+		t.Errorf("expected [1.5, 3.0], got %v", v)
+	}
+}
+
+func TestMeanCols(t *testing.T) {
+	m := New(7, 5)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*5 + j)
+		}
+	}
+	v := MeanCols(m)
+	if len(v) != 5 {
+		t.Errorf("expected length of 5, got %d", len(v))
+	}
+	for j := range v {
+		if v[j] != Avg(m, 1, j) {
+			t.Errorf("at col %d, expected %f, got %f", j, Avg(m, 1, j), v[j])
+		}
+	}
+}
+
+func TestMeanRows(t *testing.T) {
+	m := New(7, 5)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*5 + j)
+		}
+	}
+	v := MeanRows(m)
+	if len(v) != 7 {
+		t.Errorf("expected length of 7, got %d", len(v))
+	}
+	for i := range v {
+		if v[i] != Avg(m, 0, i) {
+			t.Errorf("at row %d, expected %f, got %f", i, Avg(m, 0, i), v[i])
+		}
+	}
+}
+
+func TestSumCols(t *testing.T) {
+	m := New(7, 5)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*5 + j)
+		}
+	}
+	v := SumCols(m)
+	if len(v) != 5 {
+		t.Errorf("expected length of 5, got %d", len(v))
+	}
+	for j := range v {
+		if v[j] != Sum(m, 1, j) {
+			t.Errorf("at col %d, expected %f, got %f", j, Sum(m, 1, j), v[j])
+		}
+	}
+}
+
+func BenchmarkSumCols(b *testing.B) {
+	m := New(1000, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = SumCols(m)
+	}
+}
+
+func TestSumRows(t *testing.T) {
+	m := New(7, 5)
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = float64(i*5 + j)
+		}
+	}
+	v := SumRows(m)
+	if len(v) != 7 {
+		t.Errorf("expected length of 7, got %d", len(v))
+	}
+	for i := range v {
+		if v[i] != Sum(m, 0, i) {
+			t.Errorf("at row %d, expected %f, got %f", i, Sum(m, 0, i), v[i])
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	n := Clone(m)
+	if equal, i, j := Diff(m, n); !equal || i != -1 || j != -1 {
+		t.Errorf("expected true, -1, -1, got %v, %d, %d", equal, i, j)
+	}
+	n[1][1] = 5.0
+	if equal, i, j := Diff(m, n); equal || i != 1 || j != 1 {
+		t.Errorf("expected false, 1, 1, got %v, %d, %d", equal, i, j)
+	}
+	n[1] = []float64{3.0}
+	if equal, i, j := Diff(m, n); equal || i != 1 || j != -1 {
+		t.Errorf("expected false, 1, -1, got %v, %d, %d", equal, i, j)
+	}
+	if equal, i, j := Diff(m, n[:1]); equal || i != -1 || j != -1 {
+		t.Errorf("expected false, -1, -1, got %v, %d, %d", equal, i, j)
+	}
+}
+
+func TestSprint(t *testing.T) {
+	m := [][]float64{{1.0, -2.5}, {10.0, 3.0}}
+	str := Sprint(m, 2)
+	expected := " 1.00  -2.50\n10.00   3.00"
+	if str != expected {
+		t.Errorf("expected %q, got %q", expected, str)
+	}
+	str = Sprint([][]float64{}, 2)
+	if str != "[]" {
+		t.Errorf("expected %q, got %q", "[]", str)
+	}
+}
+
+func TestMatrixString(t *testing.T) {
+	m := [][]float64{{1.0, -2.5}, {10.0, 3.0}}
+	w := Wrap(m)
+	str := fmt.Sprint(w)
+	expected := " 1  -2.5\n10     3"
+	if str != expected {
+		t.Errorf("expected %q, got %q", expected, str)
+	}
+	w[0][0] = 7.0
+	if m[0][0] != 7.0 {
+		t.Errorf("expected Wrap not to copy the data")
+	}
+}
+
+func TestCols(t *testing.T) {
+	m := [][]float64{{1.0, 2.0, 3.0}, {4.0, 5.0, 6.0}}
+	n := Cols(m, 2, 0)
+	expected := [][]float64{{3.0, 1.0}, {6.0, 4.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Cols(m, -1, -1)
+	expected = [][]float64{{3.0, 3.0}, {6.0, 6.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Cols(m)
+	if !Equal(n, [][]float64{{}, {}}) {
+		t.Errorf("expected two empty rows, got %v", n)
+	}
+	if n = Cols([][]float64{}); len(n) != 0 {
+		t.Errorf("expected an empty [][]float64, got %v", n)
+	}
+	defer func() {
+		r := recover()
+		e := "In mat.Cols() the requested column 0 is outside of bounds [-0, 0)\n"
+		if r != e {
+			t.Errorf("expected panic %q, got %v", e, r)
+		}
+	}()
+	Cols([][]float64{}, 0)
+}
+
+func TestRows(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}, {5.0, 6.0}}
+	n := Rows(m, 2, 0)
+	expected := [][]float64{{5.0, 6.0}, {1.0, 2.0}}
+	if !Equal(n, expected) {
+		t.Errorf("expected %v, got %v", expected, n)
+	}
+	n = Rows(m, -3)
+	n[0][0] = 100.0
+	if m[0][0] == 100.0 {
+		t.Errorf("expected Rows to copy the rows")
+	}
+}
+
+func TestSortRowsBy(t *testing.T) {
+	m := [][]float64{{3.0, 1.0}, {1.0, 2.0}, {2.0, 3.0}, {1.0, 4.0}}
+	SortRowsBy(m, 0)
+	expected := [][]float64{{1.0, 2.0}, {1.0, 4.0}, {2.0, 3.0}, {3.0, 1.0}}
+	if !Equal(m, expected) {
+		t.Errorf("expected %v, got %v", expected, m)
+	}
+	SortRowsBy(m, -1)
+	expected = [][]float64{{3.0, 1.0}, {1.0, 2.0}, {2.0, 3.0}, {1.0, 4.0}}
+	if !Equal(m, expected) {
+		t.Errorf("expected %v, got %v", expected, m)
+	}
+}
+
+func TestSortRowsByDesc(t *testing.T) {
+	m := [][]float64{{3.0, 1.0}, {1.0, 2.0}, {2.0, 3.0}, {1.0, 4.0}}
+	SortRowsByDesc(m, 0)
+	expected := [][]float64{{3.0, 1.0}, {2.0, 3.0}, {1.0, 2.0}, {1.0, 4.0}}
+	if !Equal(m, expected) {
+		t.Errorf("expected %v, got %v", expected, m)
+	}
+}
+
+func TestApply2(t *testing.T) {
+	m := [][]float64{{1.0, 5.0}, {3.0, 2.0}}
+	n := [][]float64{{4.0, 2.0}, {3.0, 6.0}}
+	o := Apply2(math.Max, m, n)
+	expected := [][]float64{{4.0, 5.0}, {3.0, 6.0}}
+	if !Equal(o, expected) {
+		t.Errorf("expected %v, got %v", expected, o)
+	}
+	sub := func(a, b float64) float64 {
+		return a - b
+	}
+	o = Apply2(sub, m, n)
+	if !Equal(o, Sub(m, n)) {
+		t.Errorf("expected %v, got %v", Sub(m, n), o)
+	}
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		m    [][]float64
+		rank int
+	}{
+		{[][]float64{{1.0, 2.0}, {2.0, 4.0}, {0.0, 1.0}}, 2},
+		{[][]float64{{1.0, 2.0, 3.0}, {2.0, 4.0, 6.0}}, 1},
+		{I(4), 4},
+		{New(3, 3), 0},
+		{[][]float64{}, 0},
+		{[][]float64{{1e-20, 0.0}, {0.0, 2e-20}}, 2},
+	}
+	for _, test := range tests {
+		r := Rank(test.m, 0.0)
+		if r != test.rank {
+			t.Errorf("expected rank %d for %v, got %d", test.rank, test.m, r)
+		}
+	}
+	m := [][]float64{{1.0, 0.0}, {0.0, 1e-8}}
+	if Rank(m, 1e-6) != 1 {
+		t.Errorf("expected rank 1 with tol 1e-6, got %d", Rank(m, 1e-6))
+	}
+	if Rank(m, 0.0) != 2 {
+		t.Errorf("expected rank 2 with the default tol, got %d", Rank(m, 0.0))
+	}
+}
+
+func TestCond(t *testing.T) {
+	m := [][]float64{{1.0, 0.0}, {0.0, 1e-6}}
+	if c := Cond(m); math.Abs(c-1e6) > 1e-6 {
+		t.Errorf("expected condition number of 1e6, got %f", c)
+	}
+	if c := Cond(I(3)); c != 1.0 {
+		t.Errorf("expected condition number of 1.0, got %f", c)
+	}
+	m = [][]float64{{4.0, 7.0}, {2.0, 6.0}}
+	// the inverse is [[0.6, -0.7], [-0.2, 0.4]], so the result is 13 * 1.1.
+	if c := Cond(m); math.Abs(c-14.3) > 1e-12 {
+		t.Errorf("expected condition number of 14.3, got %f", c)
+	}
+	m = [][]float64{{0.0, 1.0, 2.0}, {1.0, 0.0, 3.0}, {4.0, -3.0, 8.0}}
+	// the inverse is [[-4.5, 7, -1.5], [-2, 4, -1], [1.5, -2, 0.5]].
+	if c := Cond(m); math.Abs(c-13.0*13.0) > 1e-9 {
+		t.Errorf("expected condition number of %f, got %f", 13.0*13.0, c)
+	}
+	if c := Cond([][]float64{{1.0, 2.0}, {2.0, 4.0}}); !math.IsInf(c, 1) {
+		t.Errorf("expected +Inf for a singular matrix, got %f", c)
+	}
+	// The last pivot is about -8.9e-16 rather than exactly 0.0.
+	if c := Cond([][]float64{{1.0, 2.0}, {3.0, 6.0 + 3e-15}}); !math.IsInf(c, 1) {
+		t.Errorf("expected +Inf for a numerically singular matrix, got %f", c)
+	}
+	// Invertible, but beyond the threshold of 2 * eps * 1.0.
+	if c := Cond([][]float64{{1.0, 0.0}, {0.0, 1e-16}}); !math.IsInf(c, 1) {
+		t.Errorf("expected +Inf for diag(1.0, 1e-16), got %f", c)
+	}
+	if c := Cond([][]float64{{1.0, 0.0}, {0.0, 1e-12}}); math.Abs(c-1e12) > 1e-3 {
+		t.Errorf("expected condition number of 1e12, got %f", c)
+	}
+	defer func() {
+		r := recover()
+		e := "In mat.Cond(), the [][]float64 cannot be empty.\n"
+		if r != e {
+			t.Errorf("expected panic %q, got %v", e, r)
+		}
+	}()
+	Cond([][]float64{})
+}
+
+func TestDotBatch(t *testing.T) {
+	ms := make([][][]float64, 10)
+	ns := make([][][]float64, 10)
+	for k := range ms {
+		ms[k] = Rand(k+1, 3)
+		ns[k] = Rand(3, 10-k)
+	}
+	res := DotBatch(ms, ns)
+	if len(res) != len(ms) {
+		t.Fatalf("expected %d results, got %d", len(ms), len(res))
+	}
+	for k := range res {
+		if !Equal(res[k], Dot(ms[k], ns[k])) {
+			t.Errorf("result at index %d does not match mat.Dot", k)
+		}
+	}
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("expected a panic for mismatched dimensions")
+		}
+		s := fmt.Sprint(r)
+		if !strings.Contains(s, "at index 1") {
+			t.Errorf("expected the panic to name index 1, got %q", s)
+		}
+	}()
+	DotBatch([][][]float64{I(2), I(2)}, [][][]float64{I(2), I(3)})
+}
+
+func TestDotInto(t *testing.T) {
+	m := Rand(4, 3)
+	n := Rand(3, 5)
+	dst := Set(New(4, 5), 100.0)
+	DotInto(dst, m, n)
+	if !Equal(dst, Dot(m, n)) {
+		t.Errorf("expected %v, got %v", Dot(m, n), dst)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a mismatched output shape")
+		}
+	}()
+	DotInto(New(4, 4), m, n)
+}
+
+func BenchmarkDotAlloc(b *testing.B) {
+	m := Rand(50, 50)
+	n := Rand(50, 50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Dot(m, n)
+	}
+}
+
+func BenchmarkDotInto(b *testing.B) {
+	m := Rand(50, 50)
+	n := Rand(50, 50)
+	dst := New(50, 50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DotInto(dst, m, n)
+	}
+}
+
+func TestAxpy(t *testing.T) {
+	x := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	y := [][]float64{{1.0, 1.0}, {1.0, 1.0}}
+	Axpy(-1.0, x, y)
+	if !Equal(y, [][]float64{{0.0, -1.0}, {-2.0, -3.0}}) {
+		t.Errorf("expected [[0.0, -1.0], [-2.0, -3.0]], got %v", y)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for mismatched shapes")
+		}
+	}()
+	Axpy(1.0, x, New(2, 3))
+}
+
+func TestGemm(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 4.0}}
+	c := [][]float64{{1.0, 1.0}, {1.0, 1.0}}
+	Gemm(2.0, m, I(2), 1.0, c)
+	if !Equal(c, [][]float64{{3.0, 5.0}, {7.0, 9.0}}) {
+		t.Errorf("expected [[3.0, 5.0], [7.0, 9.0]], got %v", c)
+	}
+	n := Rand(2, 3)
+	c = [][]float64{{math.NaN(), 1.0, 2.0}, {3.0, 4.0, 5.0}}
+	Gemm(1.0, m, n, 0.0, c)
+	if !Equal(c, Dot(m, n)) {
+		t.Errorf("expected %v, got %v", Dot(m, n), c)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a mismatched output shape")
+		}
+	}()
+	Gemm(1.0, m, n, 1.0, New(2, 2))
+}
+
+func TestForeachValue(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0}, {}, {4.0, 5.0, 6.0}}
+	var got []float64
+	ForeachValue(m, func(x float64) {
+		got = append(got, x)
+	})
+	want := []float64{1.0, 2.0, 3.0, 4.0, 5.0, 6.0}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("at index %d, expected %f, got %f", i, want[i], got[i])
+		}
+	}
+}
+
+func TestApplyRows(t *testing.T) {
+	m := [][]float64{{1.0, 3.0}, {2.0, 2.0}}
+	row := m[0]
+	ApplyRows(m, func(r []float64) []float64 {
+		return []float64{r[0] + r[1], r[0] * r[1]}
+	})
+	if !Equal(m, [][]float64{{4.0, 3.0}, {4.0, 4.0}}) {
+		t.Errorf("expected [[4.0, 3.0], [4.0, 4.0]], got %v", m)
+	}
+	if row[0] != 4.0 {
+		t.Errorf("expected the existing rows to be updated in place")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic when the function changes the length")
+		}
+	}()
+	ApplyRows(m, func(r []float64) []float64 {
+		return r[:1]
+	})
+}
+
+func TestApplyCols(t *testing.T) {
+	m := [][]float64{{1.0, 2.0}, {3.0, 2.0}}
+	ApplyCols(m, func(c []float64) []float64 {
+		sum := c[0] + c[1]
+		c[0] /= sum
+		c[1] /= sum
+		return c
+	})
+	if !Equal(m, [][]float64{{0.25, 0.5}, {0.75, 0.5}}) {
+		t.Errorf("expected [[0.25, 0.5], [0.75, 0.5]], got %v", m)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic when the function changes the length")
+		}
+	}()
+	ApplyCols(m, func(c []float64) []float64 {
+		return append(c, 1.0)
+	})
+}
+
+func TestLogDet(t *testing.T) {
+	tests := []struct {
+		m    [][]float64
+		log  float64
+		sign float64
+	}{
+		{I(3), 0.0, 1.0},
+		{[][]float64{{4.0, 7.0}, {2.0, 6.0}}, math.Log(10.0), 1.0},
+		{[][]float64{{0.0, 1.0}, {1.0, 0.0}}, 0.0, -1.0},
+		{[][]float64{{1e-200, 0.0}, {0.0, -1e-200}}, -400.0 * math.Log(10.0), -1.0},
+		{[][]float64{{1.0, 2.0}, {2.0, 4.0}}, math.Inf(-1), 0.0},
+	}
+	for _, test := range tests {
+		l, s := LogDet(test.m)
+		if s != test.sign {
+			t.Errorf("expected sign %f for %v, got %f", test.sign, test.m, s)
+		}
+		if math.IsInf(test.log, -1) {
+			if !math.IsInf(l, -1) {
+				t.Errorf("expected -Inf for %v, got %f", test.m, l)
+			}
+		} else if math.Abs(l-test.log) > 1e-9 {
+			t.Errorf("expected %f for %v, got %f", test.log, test.m, l)
+		}
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a non-square [][]float64")
+		}
+	}()
+	LogDet(New(2, 3))
+}
+
+func TestSymmetrize(t *testing.T) {
+	m := Cov(Rand(20, 4))
+	for i := range m {
+		for j := range m[i] {
+			if i != j {
+				m[i][j] += float64(i-j) * 1e-12
+			}
+		}
+	}
+	c := Clone(m)
+	Symmetrize(m)
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] != m[j][i] {
+				t.Errorf("expected [%d][%d] and [%d][%d] to be equal, got %v and %v",
+					i, j, j, i, m[i][j], m[j][i])
+			}
+			if math.Abs(m[i][j]-c[i][j]) > 1e-11 {
+				t.Errorf("expected [%d][%d] to be near %v, got %v", i, j, c[i][j], m[i][j])
+			}
+		}
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a non-square [][]float64")
+		}
+	}()
+	Symmetrize(New(2, 3))
+}
+
+func TestToMarkdown(t *testing.T) {
+	m := [][]float64{{1.0, -2.5}, {10.0, 3.0}}
+	s := ToMarkdown(m, []string{"x", "a|b"}, 2)
+	e := "| x | a\\|b |\n| ---: | ---: |\n| 1.00 | -2.50 |\n| 10.00 | 3.00 |"
+	if s != e {
+		t.Errorf("expected\n%s\ngot\n%s", e, s)
+	}
+	s = ToMarkdown(m, nil, -1)
+	e = "| 0 | 1 |\n| ---: | ---: |\n| 1 | -2.5 |\n| 10 | 3 |"
+	if s != e {
+		t.Errorf("expected\n%s\ngot\n%s", e, s)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic when the headers do not match the columns")
+		}
+	}()
+	ToMarkdown(m, []string{"x"}, 2)
+}
+
+func TestTJagged(t *testing.T) {
+	m := [][]float64{{1.0, 2.0, 3.0}, {4.0}, {}}
+	n := TJagged(m, -1.0)
+	e := [][]float64{{1.0, 4.0, -1.0}, {2.0, -1.0, -1.0}, {3.0, -1.0, -1.0}}
+	if !Equal(n, e) {
+		t.Errorf("expected %v, got %v", e, n)
+	}
+	if len(TJagged([][]float64{}, 0.0)) != 0 {
+		t.Errorf("expected an empty result for an empty [][]float64")
+	}
+	if len(TJagged([][]float64{{}, {}}, 0.0)) != 0 {
+		t.Errorf("expected an empty result when all rows are empty")
+	}
+	m = Rand(4, 7)
+	if !Equal(TJagged(m, 0.0), T(m)) {
+		t.Errorf("expected the same result as mat.T for non-jagged input")
+	}
+}
+
+func TestTry(t *testing.T) {
+	var res [][]float64
+	err := Try(func() {
+		res = Dot(I(2), I(2))
+	})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if !Equal(res, I(2)) {
+		t.Errorf("expected %v, got %v", I(2), res)
+	}
+	err = Try(func() {
+		New(-1, 2)
+	})
+	if err == nil || !strings.Contains(err.Error(), "In mat.New()") {
+		t.Errorf("expected an error from mat.New(), got %v", err)
+	}
+	err = Try(func() {
+		var m [][]float64
+		_ = m[1]
+	})
+	if err == nil {
+		t.Errorf("expected an error for a runtime panic")
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := [][]float64{{1.0, 4.0}, {9.0, 16.0}}
+	n := Map(math.Sqrt, m)
+	if !Equal(n, [][]float64{{1.0, 2.0}, {3.0, 4.0}}) {
+		t.Errorf("expected [[1.0, 2.0], [3.0, 4.0]], got %v", n)
+	}
+	if m[0][1] != 4.0 {
+		t.Errorf("the [][]float64 was mutated: %v", m)
+	}
+}
+
+func TestOnes(t *testing.T) {
+	m := Ones(2, 3)
+	if !Equal(m, [][]float64{{1.0, 1.0, 1.0}, {1.0, 1.0, 1.0}}) {
+		t.Errorf("expected a 2 by 3 [][]float64 of 1.0s, got %v", m)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a non-positive dimension")
+		}
+	}()
+	Ones(0, 3)
+}
+
+func TestInc(t *testing.T) {
+	m := Inc(2, 3)
+	if !Equal(m, [][]float64{{0.0, 1.0, 2.0}, {3.0, 4.0, 5.0}}) {
+		t.Errorf("expected [[0.0, 1.0, 2.0], [3.0, 4.0, 5.0]], got %v", m)
+	}
+}
+
+func TestReset(t *testing.T) {
+	m := Inc(3, 2)
+	Reset(m)
+	if !Equal(m, New(3, 2)) {
+		t.Errorf("expected all elements to be 0.0, got %v", m)
+	}
+}
+
+func TestBootstrapRows(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := Inc(5, 2)
+	b := BootstrapRows(m, 50, r)
+	if len(b) != 50 {
+		t.Fatalf("expected 50 rows, got %d", len(b))
+	}
+	seen := make(map[float64]bool)
+	for i := range b {
+		if len(b[i]) != 2 || b[i][1] != b[i][0]+1.0 || int(b[i][0])%2 != 0 {
+			t.Errorf("row %d, %v, is not a row of the original", i, b[i])
+		}
+		seen[b[i][0]] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("expected more than one distinct row, got %v", seen)
+	}
+	b[0][0] = 100.0
+	if !Equal(m, Inc(5, 2)) {
+		t.Errorf("the [][]float64 was mutated: %v", m)
+	}
+	if len(BootstrapRows(m, 0, nil)) != 0 {
+		t.Errorf("expected no rows when n is 0")
+	}
+}
+
+func TestKFold(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	m := Inc(11, 1)
+	train, test := KFold(m, 3, r)
+	if len(train) != 3 || len(test) != 3 {
+		t.Fatalf("expected 3 folds, got %d and %d", len(train), len(test))
+	}
+	seen := make(map[float64]int)
+	for f := range test {
+		if len(test[f]) < 3 || len(test[f]) > 4 {
+			t.Errorf("expected fold %d to have 3 or 4 rows, got %d", f, len(test[f]))
+		}
+		if len(train[f])+len(test[f]) != len(m) {
+			t.Errorf("expected fold %d to cover all %d rows", f, len(m))
+		}
+		for i := range test[f] {
+			seen[test[f][i][0]]++
+		}
+		for i := range train[f] {
+			for j := range test[f] {
+				if train[f][i][0] == test[f][j][0] {
+					t.Errorf("row %v is in both sets of fold %d", train[f][i], f)
+				}
+			}
+		}
+	}
+	for i := range m {
+		if seen[m[i][0]] != 1 {
+			t.Errorf("expected row %d in exactly one test fold, got %d", i, seen[m[i][0]])
+		}
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic when k is larger than the number of rows")
+		}
+	}()
+	KFold(m, 12, r)
+}
+
+func TestTrainTestSplit(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	m := Inc(10, 2)
+	train, test := TrainTestSplit(m, 0.25, r)
+	if len(train) != 7 || len(test) != 3 {
+		t.Errorf("expected 7 training and 3 test rows, got %d and %d", len(train), len(test))
+	}
+	sum := Sum(train) + Sum(test)
+	if sum != Sum(m) {
+		t.Errorf("expected the rows to be split without loss, got a sum of %f", sum)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a test fraction of 1.0")
+		}
+	}()
+	TrainTestSplit(m, 1.0, r)
+}
+
+func TestTrainTestSplitXY(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	x := Inc(20, 3)
+	yy := make([][]float64, len(x))
+	for i := range x {
+		yy[i] = []float64{x[i][0] * 2.0}
+	}
+	xTrain, xTest, yTrain, yTest := TrainTestSplitXY(x, yy, 0.5, r)
+	if len(xTrain) != 10 || len(xTest) != 10 || len(yTrain) != 10 || len(yTest) != 10 {
+		t.Fatalf("expected 10 rows in each set")
+	}
+	for i := range xTrain {
+		if yTrain[i][0] != xTrain[i][0]*2.0 {
+			t.Errorf("training row %d of x and y do not correspond", i)
+		}
+	}
+	for i := range xTest {
+		if yTest[i][0] != xTest[i][0]*2.0 {
+			t.Errorf("test row %d of x and y do not correspond", i)
+		}
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for mismatched rows")
+		}
+	}()
+	TrainTestSplitXY(x, yy[:5], 0.5, r)
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	predicted := []int{0, 1, 1, 2}
+	actual := []int{0, 1, 2, 2}
+	c := ConfusionMatrix(predicted, actual, 3)
+	e := [][]float64{{1.0, 0.0, 0.0}, {0.0, 1.0, 0.0}, {0.0, 1.0, 1.0}}
+	if !Equal(c, e) {
+		t.Errorf("expected %v, got %v", e, c)
+	}
+	defer func() {
+		r := recover()
+		e := "In mat.ConfusionMatrix(), the label 3 at index 1 is outside of range [0, 3).\n"
+		if r != e {
+			t.Errorf("expected panic %q, got %v", e, r)
+		}
+	}()
+	ConfusionMatrix([]int{0, 3}, []int{0, 1}, 3)
+}
+
+func TestAccuracy(t *testing.T) {
+	if a := Accuracy([]int{0, 1, 1, 2}, []int{0, 1, 2, 2}); a != 0.75 {
+		t.Errorf("expected 0.75, got %f", a)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for mismatched lengths")
+		}
+	}()
+	Accuracy([]int{0}, []int{0, 1})
+}
+
+func TestPrecisionRecallF1(t *testing.T) {
+	predicted := []int{0, 1, 1, 2, 0}
+	actual := []int{0, 1, 2, 2, 0}
+	p, r, f1 := PrecisionRecallF1(predicted, actual, 4)
+	ep := []float64{1.0, 0.5, 1.0, 0.0}
+	er := []float64{1.0, 1.0, 0.5, 0.0}
+	ef := []float64{1.0, 2.0 / 3.0, 2.0 / 3.0, 0.0}
+	for k := range ep {
+		if p[k] != ep[k] {
+			t.Errorf("expected precision %f for class %d, got %f", ep[k], k, p[k])
+		}
+		if r[k] != er[k] {
+			t.Errorf("expected recall %f for class %d, got %f", er[k], k, r[k])
+		}
+		if math.Abs(f1[k]-ef[k]) > 1e-12 {
+			t.Errorf("expected F1 %f for class %d, got %f", ef[k], k, f1[k])
+		}
+	}
+}
+
+func TestLinReg(t *testing.T) {
+	x := [][]float64{{1.0}, {2.0}, {3.0}}
+	y := []float64{3.0, 5.0, 7.0}
+	b := LinReg(x, y, true)
+	if len(b) != 2 || math.Abs(b[0]-1.0) > 1e-12 || math.Abs(b[1]-2.0) > 1e-12 {
+		t.Errorf("expected [1.0, 2.0], got %v", b)
+	}
+	x = [][]float64{{1.0, 0.0}, {0.0, 1.0}, {1.0, 1.0}, {2.0, 1.0}}
+	y = []float64{2.0, -1.0, 1.0, 3.0}
+	b = LinReg(x, y, false)
+	if len(b) != 2 || math.Abs(b[0]-2.0) > 1e-12 || math.Abs(b[1]+1.0) > 1e-12 {
+		t.Errorf("expected [2.0, -1.0], got %v", b)
+	}
+	collinear := [][][]float64{
+		{{1.0, 2.0}, {2.0, 4.0}, {3.0, 6.0}},
+		{{1.0, 2.0 + 1e-12}, {2.0, 4.0 - 1e-12}, {3.0, 6.0 + 1e-12}},
+	}
+	for _, x := range collinear {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Errorf("expected a panic for collinear columns %v", x)
+				}
+				if !strings.Contains(fmt.Sprint(r), "singular") {
+					t.Errorf("expected the panic to mention a singular matrix, got %v", r)
+				}
+			}()
+			LinReg(x, []float64{1.0, 2.0, 3.0}, false)
+		}()
+	}
+}
+
+func TestSigmoid(t *testing.T) {
+	m := [][]float64{{-1000.0, 0.0}, {1000.0, 2.0}}
+	n := Sigmoid(m)
+	e := [][]float64{{0.0, 0.5}, {1.0, 1.0 / (1.0 + math.Exp(-2.0))}}
+	if !Equal(n, e) {
+		t.Errorf("expected %v, got %v", e, n)
+	}
+	if m[0][0] != -1000.0 {
+		t.Errorf("the [][]float64 was mutated: %v", m)
+	}
+}
+
+func TestSigmoidPrime(t *testing.T) {
+	m := [][]float64{{-1000.0, 0.0}, {1000.0, 2.0}}
+	n := SigmoidPrime(m)
+	s := 1.0 / (1.0 + math.Exp(-2.0))
+	e := [][]float64{{0.0, 0.25}, {0.0, s * (1.0 - s)}}
+	if !Equal(n, e) {
+		t.Errorf("expected %v, got %v", e, n)
+	}
+	if m[0][0] != -1000.0 {
+		t.Errorf("the [][]float64 was mutated: %v", m)
+	}
+}
+
+func TestTanh(t *testing.T) {
+	m := Tanh([][]float64{{-1000.0, 0.0}, {0.5, 1000.0}})
+	e := [][]float64{{-1.0, 0.0}, {math.Tanh(0.5), 1.0}}
+	if !Equal(m, e) {
+		t.Errorf("expected %v, got %v", e, m)
+	}
+}
+
+func TestReLU(t *testing.T) {
+	n := [][]float64{{-2.0, 0.0}, {3.0, -0.5}}
+	m := ReLU(n)
+	if !Equal(m, [][]float64{{0.0, 0.0}, {3.0, 0.0}}) {
+		t.Errorf("expected [[0.0, 0.0], [3.0, 0.0]], got %v", m)
+	}
+	if n[0][0] != -2.0 {
+		t.Errorf("the [][]float64 was mutated: %v", n)
+	}
+}
+
+func TestExp(t *testing.T) {
+	m := Exp([][]float64{{0.0}, {1.0}})
+	if !Equal(m, [][]float64{{1.0}, {math.E}}) {
+		t.Errorf("expected [[1.0], [e]], got %v", m)
+	}
+}
+
+func TestLog(t *testing.T) {
+	m := Log([][]float64{{1.0, math.E}, {0.0, -1.0}})
+	if m[0][0] != 0.0 || m[0][1] != 1.0 || !math.IsInf(m[1][0], -1) || !math.IsNaN(m[1][1]) {
+		t.Errorf("expected [[0.0, 1.0], [-Inf, NaN]], got %v", m)
+	}
+	defer func() {
+		r := recover()
+		e := "In mat.LogStrict(), the element at [1][0] is -1.000000, which must be greater than 0.0.\n"
+		if r != e {
+			t.Errorf("expected panic %q, got %v", e, r)
+		}
+	}()
+	LogStrict([][]float64{{1.0}, {-1.0}})
+}
+
+func TestSqrt(t *testing.T) {
+	m := Sqrt([][]float64{{4.0, 0.0}, {-1.0, 9.0}})
+	if m[0][0] != 2.0 || m[0][1] != 0.0 || !math.IsNaN(m[1][0]) || m[1][1] != 3.0 {
+		t.Errorf("expected [[2.0, 0.0], [NaN, 3.0]], got %v", m)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a negative element")
+		}
+	}()
+	SqrtStrict([][]float64{{4.0, -1.0}})
+}
+
+func TestLogClamped(t *testing.T) {
+	m := LogClamped([][]float64{{0.0, -3.0}, {1.0, math.E}}, 1e-12)
+	e := math.Log(1e-12)
+	if !Equal(m, [][]float64{{e, e}, {0.0, 1.0}}) {
+		t.Errorf("expected [[%f, %f], [0.0, 1.0]], got %v", e, e, m)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic for a negative eps")
+		}
+	}()
+	LogClamped(m, -1.0)
 }
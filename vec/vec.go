@@ -30,6 +30,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 )
 
 var (
@@ -45,7 +46,25 @@ var (
 		"\ngocrunch/vec error.\nIn vec.%s, in the second []float64, zero value found at index %d.\n",
 		"\ngocrunch/vec error.\nIn vec.%s, the length of slice %d is not divisible by the stride %d.\n",
 		"\ngocrunch/vec error.\nIn vec.%s, the first argument %f must be less than the second, %f.\n",
-		"\ngocrunch/vec error.\nIn vec.%s, expected 0 to 0 float64 arguments, but got %d.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, expected 0 to 2 float64 arguments, but got %d.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the %s must be greater than 0, but received %d.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the %s %d is larger than the length of the []float64, %d.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, unknown mode %q, expected one of %s.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the norm of the passed []float64 is 0.0.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, p must be at least 1.0, but received %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the step cannot be negative, but received %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the sum of the weights is 0.0.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, alpha must be in (0, 1], but received %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the []float64 must have at least %d elements, but has %d.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the %s cannot be negative, but received %d.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the fraction must be in [0, 0.5), but received %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the old range cannot be empty, but both ends are %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the weight at index %d is negative: %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, cannot draw %d samples without replacement from %d non-zero weights.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the actual values have zero variance.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the element at index %d is %f, which must be %s.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, eps must be greater than 0.0, but received %f.\n",
+		"\ngocrunch/vec error.\nIn vec.%s, the range must be finite, but received %f and %f.\n",
 	}
 )
 
@@ -202,7 +221,9 @@ For 1 argument, such as
 
 	vec.Rand(x, arg)
 
-the range is [0, arg) for arg > 0, or (arg, 0] if arg < 0.
+the range is [0, arg) for arg > 0, or (arg, 0] if arg < 0. In both cases
+the values are rand.Float64() * arg, so 0.0 can be produced but arg cannot.
+If arg is 0.0, all of the elements are 0.0.
 
 For 2 arguments, such as
 
@@ -223,8 +244,12 @@ func Rand(x int, args ...float64) []float64 {
 			v[i] = rand.Float64() * args[0]
 		}
 	case 2:
-		if !(args[2] > args[1]) {
-			panic(fmt.Sprintf(errStrings[10], "Rand()", args[0], args[1]))
+		from, to := args[0], args[1]
+		if !(from < to) {
+			panic(fmt.Sprintf(errStrings[10], "Rand()", from, to))
+		}
+		for i := range v {
+			v[i] = rand.Float64()*(to-from) + from
 		}
 	default:
 		panic(fmt.Sprintf(errStrings[11], "Rand()", len(args)))
@@ -580,3 +605,1314 @@ func Dot(v1, v2 []float64) float64 {
 	}
 	return result
 }
+
+/*
+Unique returns a new []float64 containing the distinct elements of the passed
+[]float64, in the order in which they are first seen. For example:
+
+	v := []float64{3.0, 1.0, 3.0, 2.0, 1.0}
+	u := vec.Unique(v) // u is {3.0, 1.0, 2.0}
+
+Elements are compared for exact equality, so 0.0 and -0.0 are considered the
+same value. Since NaN is not equal to itself, every NaN in the passed
+[]float64 is treated as distinct, and all of them are kept in the result.
+
+The original []float64 is not mutated in this function.
+*/
+func Unique(v []float64) []float64 {
+	seen := make(map[float64]bool)
+	u := []float64{}
+	for i := range v {
+		if seen[v[i]] {
+			continue
+		}
+		seen[v[i]] = true
+		u = append(u, v[i])
+	}
+	return u
+}
+
+/*
+Windows returns the windows of a []float64 of a given size, with the start of
+each window advancing by the passed step. For example:
+
+	v := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+	w := vec.Windows(v, 3, 1) // w is [[1.0, 2.0, 3.0], [2.0, 3.0, 4.0], [3.0, 4.0, 5.0]]
+	w = vec.Windows(v, 2, 2) // w is [[1.0, 2.0], [3.0, 4.0]]
+
+As seen in the second example, if the last window would be shorter than the
+passed size, it is dropped. Each window is a copy, and the original []float64
+is not mutated in this function. Both the size and the step must be greater
+than 0, and the size cannot be larger than the length of the []float64.
+*/
+func Windows(v []float64, size, step int) [][]float64 {
+	if size <= 0 {
+		panic(fmt.Sprintf(errStrings[12], "Windows()", "size", size))
+	}
+	if step <= 0 {
+		panic(fmt.Sprintf(errStrings[12], "Windows()", "step", step))
+	}
+	if size > len(v) {
+		panic(fmt.Sprintf(errStrings[13], "Windows()", "size", size, len(v)))
+	}
+	w := [][]float64{}
+	for i := 0; i+size <= len(v); i += step {
+		w = append(w, Clone(v[i:i+size]))
+	}
+	return w
+}
+
+/*
+Convolve returns the discrete, linear convolution of two []float64s. The
+length of the result depends on the passed mode, which follows the semantics
+of NumPy's convolve function. Here N is the length of the longer of the two
+[]float64s, and M the length of the shorter one:
+
+	vec.Convolve(v, kernel, "full") // length N+M-1, every point of overlap
+	vec.Convolve(v, kernel, "same") // length N, centered on the "full" result
+	vec.Convolve(v, kernel, "valid") // length N-M+1, points of complete overlap
+
+For example:
+
+	v := []float64{1.0, 2.0, 3.0}
+	k := []float64{0.0, 1.0, 0.5}
+	c := vec.Convolve(v, k, "full") // c is {0.0, 1.0, 2.5, 4.0, 1.5}
+	c = vec.Convolve(v, k, "same") // c is {1.0, 2.5, 4.0}
+	c = vec.Convolve(v, k, "valid") // c is {2.5}
+
+Neither of the passed []float64s can be empty, and the mode must be one of
+"full", "same", or "valid", otherwise this function will panic. The passed
+[]float64s are not mutated in this function.
+*/
+func Convolve(v, kernel []float64, mode string) []float64 {
+	if len(v) == 0 {
+		panic(fmt.Sprintf(errStrings[0], "Convolve()", "Convolve()"))
+	}
+	if len(kernel) == 0 {
+		panic(fmt.Sprintf(errStrings[0], "Convolve()", "Convolve()"))
+	}
+	n, m := len(v), len(kernel)
+	if m > n {
+		n, m = m, n
+	}
+	full := make([]float64, len(v)+len(kernel)-1)
+	for i := range v {
+		for j := range kernel {
+			full[i+j] += v[i] * kernel[j]
+		}
+	}
+	switch mode {
+	case "full":
+		return full
+	case "same":
+		start := (m - 1) / 2
+		return full[start : start+n]
+	case "valid":
+		return full[m-1 : n]
+	default:
+		panic(fmt.Sprintf(errStrings[14], "Convolve()", mode, "\"full\", \"same\", or \"valid\""))
+	}
+}
+
+/*
+SumKahan adds all elements in a []float64 using Kahan (compensated)
+summation, in the improved form due to Neumaier. A running compensation term
+keeps track of the low order bits which are lost in each addition, which
+greatly reduces the rounding error for long []float64s, or when the elements
+span many orders of magnitude. For example:
+
+	v := []float64{1.0, 1e-16, 1e-16, 1e-16, 1e-16}
+	s := vec.Sum(v) // s is 1.0
+	s = vec.SumKahan(v) // s is 1.0000000000000004
+
+This function is a few times slower than vec.Sum(), so it should be used when
+the accuracy of the result matters more than speed. This function does not
+alter the original []float64.
+*/
+func SumKahan(v []float64) float64 {
+	sum := 0.0
+	c := 0.0
+	for i := range v {
+		t := sum + v[i]
+		if math.Abs(sum) >= math.Abs(v[i]) {
+			c += (sum - t) + v[i]
+		} else {
+			c += (v[i] - t) + sum
+		}
+		sum = t
+	}
+	return sum + c
+}
+
+/*
+DotKahan returns the sum of the element-wise multiplication of two []float64s
+passed to it, just like vec.Dot(), but accumulates the products using the
+same compensated summation as vec.SumKahan(). This trades some speed for a
+noticeably smaller rounding error on large []float64s.
+
+The passed slices must have the same length, and they are not altered in this
+function.
+*/
+func DotKahan(v1, v2 []float64) float64 {
+	if len(v1) != len(v2) {
+		panic(fmt.Sprintf(errStrings[5], "DotKahan()", len(v1), len(v2)))
+	}
+	sum := 0.0
+	c := 0.0
+	for i := range v1 {
+		p := v1[i] * v2[i]
+		t := sum + p
+		if math.Abs(sum) >= math.Abs(p) {
+			c += (sum - t) + p
+		} else {
+			c += (p - t) + sum
+		}
+		sum = t
+	}
+	return sum + c
+}
+
+/*
+Reduce folds a function over all elements of a []float64, starting from the
+passed initial value. The function receives the accumulated value so far,
+and the current element, and returns the new accumulated value. Consider:
+
+	max := func(acc, val float64) float64 {
+		return math.Max(acc, val)
+	}
+	v := []float64{1.0, 3.0, 2.0}
+	m := vec.Reduce(v, math.Inf(-1), max) // m is 3.0
+
+This function does not alter the original []float64.
+*/
+func Reduce(v []float64, init float64, f func(acc, val float64) float64) float64 {
+	acc := init
+	for i := range v {
+		acc = f(acc, v[i])
+	}
+	return acc
+}
+
+/*
+Norm returns the Euclidean norm (2-norm) of a []float64, which is the square
+root of the sum of the squares of its elements. Consider:
+
+	v := []float64{3.0, 4.0}
+	n := vec.Norm(v) // 5.0
+
+This function does not alter the original []float64.
+*/
+func Norm(v []float64) float64 {
+	return math.Sqrt(Dot(v, v))
+}
+
+/*
+Normalize returns a copy of a []float64 which is scaled to have a norm of 1.0,
+by dividing each element by vec.Norm(). Consider:
+
+	v := []float64{3.0, 4.0}
+	u := vec.Normalize(v) // u is {0.6, 0.8}
+
+The norm of the passed []float64 cannot be 0.0, and such condition will
+cause a panic. The original []float64 is not mutated in this function.
+*/
+func Normalize(v []float64) []float64 {
+	n := Norm(v)
+	if n == 0.0 {
+		panic(fmt.Sprintf(errStrings[15], "Normalize()"))
+	}
+	c := Clone(v)
+	for i := range c {
+		c[i] /= n
+	}
+	return c
+}
+
+/*
+NormalizeInPlace scales a []float64 in place to have a norm of 1.0, by
+dividing each element by vec.Norm(). It is identical to vec.Normalize(),
+except that no new []float64 is allocated.
+
+The norm of the passed []float64 cannot be 0.0, and such condition will
+cause a panic. The passed []float64 is mutated in this function.
+*/
+func NormalizeInPlace(v []float64) {
+	n := Norm(v)
+	if n == 0.0 {
+		panic(fmt.Sprintf(errStrings[15], "NormalizeInPlace()"))
+	}
+	for i := range v {
+		v[i] /= n
+	}
+}
+
+/*
+Softmax returns a new []float64 where each element is the exponential of the
+corresponding element of the passed []float64, divided by the sum of all of
+the exponentials. The elements of the result are positive, and sum to 1.0.
+Consider:
+
+	v := []float64{1.0, 1.0}
+	s := vec.Softmax(v) // s is {0.5, 0.5}
+
+To avoid overflow, the maximum element is subtracted from all elements
+before they are exponentiated, which does not change the result. The passed
+[]float64 cannot be empty, and it is not mutated in this function.
+*/
+func Softmax(v []float64) []float64 {
+	if len(v) == 0 {
+		panic(fmt.Sprintf(errStrings[0], "Softmax()", "Softmax()"))
+	}
+	max := v[0]
+	for i := range v {
+		if v[i] > max {
+			max = v[i]
+		}
+	}
+	c := make([]float64, len(v))
+	sum := 0.0
+	for i := range v {
+		c[i] = math.Exp(v[i] - max)
+		sum += c[i]
+	}
+	for i := range c {
+		c[i] /= sum
+	}
+	return c
+}
+
+/*
+CosineSim returns the cosine similarity of two []float64s, which is the
+cosine of the angle between them, given by
+
+	vec.Dot(v, w) / (vec.Norm(v) * vec.Norm(w))
+
+The result is 1.0 for []float64s pointing in the same direction, 0.0 for
+orthogonal ones, and -1.0 for opposite ones. The passed []float64s must have
+the same length, and neither of them can have a norm of 0.0, since the angle
+is then undefined. The passed slices are not altered in this function.
+*/
+func CosineSim(v, w []float64) float64 {
+	if len(v) != len(w) {
+		panic(fmt.Sprintf(errStrings[5], "CosineSim()", len(v), len(w)))
+	}
+	nv, nw := Norm(v), Norm(w)
+	if nv == 0.0 || nw == 0.0 {
+		panic(fmt.Sprintf(errStrings[15], "CosineSim()"))
+	}
+	return Dot(v, w) / (nv * nw)
+}
+
+/*
+Dist returns the Euclidean distance between two []float64s, which is the
+square root of the sum of the squared differences of their elements.
+Consider:
+
+	v := []float64{0.0, 0.0}
+	w := []float64{3.0, 4.0}
+	d := vec.Dist(v, w) // 5.0
+
+The passed []float64s must have the same length, and they are not altered in
+this function.
+*/
+func Dist(v, w []float64) float64 {
+	if len(v) != len(w) {
+		panic(fmt.Sprintf(errStrings[5], "Dist()", len(v), len(w)))
+	}
+	sum := 0.0
+	for i := range v {
+		d := v[i] - w[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+/*
+DistManhattan returns the Manhattan (taxicab) distance between two
+[]float64s, which is the sum of the absolute differences of their elements.
+Consider:
+
+	v := []float64{0.0, 0.0}
+	w := []float64{3.0, -4.0}
+	d := vec.DistManhattan(v, w) // 7.0
+
+The passed []float64s must have the same length, and they are not altered in
+this function.
+*/
+func DistManhattan(v, w []float64) float64 {
+	if len(v) != len(w) {
+		panic(fmt.Sprintf(errStrings[5], "DistManhattan()", len(v), len(w)))
+	}
+	sum := 0.0
+	for i := range v {
+		sum += math.Abs(v[i] - w[i])
+	}
+	return sum
+}
+
+/*
+DistMinkowski returns the Minkowski distance of order p between two
+[]float64s, which is the p-th root of the sum of the absolute differences of
+their elements raised to the power p. This generalizes the other distances
+in this package:
+
+	vec.DistMinkowski(v, w, 1.0) // same as vec.DistManhattan(v, w)
+	vec.DistMinkowski(v, w, 2.0) // same as vec.Dist(v, w)
+	vec.DistMinkowski(v, w, math.Inf(1)) // the largest absolute difference
+
+The order p must be at least 1.0, since otherwise the result is not a
+distance. The passed []float64s must have the same length, and they are not
+altered in this function.
+*/
+func DistMinkowski(v, w []float64, p float64) float64 {
+	if len(v) != len(w) {
+		panic(fmt.Sprintf(errStrings[5], "DistMinkowski()", len(v), len(w)))
+	}
+	if !(p >= 1.0) {
+		panic(fmt.Sprintf(errStrings[16], "DistMinkowski()", p))
+	}
+	if math.IsInf(p, 1) {
+		max := 0.0
+		for i := range v {
+			max = math.Max(max, math.Abs(v[i]-w[i]))
+		}
+		return max
+	}
+	sum := 0.0
+	for i := range v {
+		sum += math.Pow(math.Abs(v[i]-w[i]), p)
+	}
+	return math.Pow(sum, 1.0/p)
+}
+
+/*
+Gradient returns the gradient of a function at the point x, approximated
+using central finite differences with step h. That is, element i of the
+result is
+
+	(f(x + h*e_i) - f(x - h*e_i)) / (2*h)
+
+where e_i is the unit vector along dimension i. Consider:
+
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + 3.0*x[1]
+	}
+	g := vec.Gradient(f, []float64{1.0, 1.0}, 0.0) // g is about {2.0, 3.0}
+
+If h is 0.0, then a default step of 1e-6 is used. The step cannot be
+negative. This function calls f 2*len(x) times, and x is not altered in
+this function.
+*/
+func Gradient(f func([]float64) float64, x []float64, h float64) []float64 {
+	if h < 0.0 {
+		panic(fmt.Sprintf(errStrings[17], "Gradient()", h))
+	}
+	if h == 0.0 {
+		h = 1e-6
+	}
+	g := make([]float64, len(x))
+	xh := Clone(x)
+	for i := range x {
+		xh[i] = x[i] + h
+		fp := f(xh)
+		xh[i] = x[i] - h
+		fm := f(xh)
+		xh[i] = x[i]
+		g[i] = (fp - fm) / (2.0 * h)
+	}
+	return g
+}
+
+/*
+Histogram counts the elements of a []float64 in the passed number of bins of
+equal width, spanning from the smallest to the largest element. It returns
+the count of elements in each bin, along with the edges of the bins, which
+has a length of bins+1. Consider:
+
+	v := []float64{1.0, 2.0, 2.0, 3.0, 5.0}
+	counts, edges := vec.Histogram(v, 2)
+	// counts is {3, 2}, edges is {1.0, 3.0, 5.0}
+
+Each bin includes its left edge, but not its right edge, except for the last
+bin which includes both. If all elements are equal, then the range is taken
+to be 0.5 on either side of that value. NaN and infinite elements are not
+counted, and do not affect the range.
+
+The number of bins must be greater than 0, and the passed []float64 must
+have at least one finite element. To fix the range of the bins, look at
+vec.HistogramRange(). The passed []float64 is not altered in this function.
+*/
+func Histogram(v []float64, bins int) ([]int, []float64) {
+	if bins <= 0 {
+		panic(fmt.Sprintf(errStrings[12], "Histogram()", "number of bins", bins))
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	seen := false
+	for i := range v {
+		if math.IsNaN(v[i]) || math.IsInf(v[i], 0) {
+			continue
+		}
+		seen = true
+		lo = math.Min(lo, v[i])
+		hi = math.Max(hi, v[i])
+	}
+	if !seen {
+		panic(fmt.Sprintf(errStrings[0], "Histogram()", "Histogram()"))
+	}
+	if lo == hi {
+		lo -= 0.5
+		hi += 0.5
+	}
+	return HistogramRange(v, bins, lo, hi)
+}
+
+/*
+HistogramRange counts the elements of a []float64 in the passed number of
+bins of equal width, spanning from lo to hi. It is identical to
+vec.Histogram(), except that the range is fixed by the caller, and the
+elements outside of [lo, hi] are not counted. Consider:
+
+	v := []float64{-1.0, 0.5, 1.5, 2.0, 7.0}
+	counts, edges := vec.HistogramRange(v, 2, 0.0, 2.0)
+	// counts is {1, 2}, edges is {0.0, 1.0, 2.0}
+
+The number of bins must be greater than 0, lo and hi must be finite, and lo
+must be less than hi. The passed []float64 is not altered in this function.
+*/
+func HistogramRange(v []float64, bins int, lo, hi float64) ([]int, []float64) {
+	if bins <= 0 {
+		panic(fmt.Sprintf(errStrings[12], "HistogramRange()", "number of bins", bins))
+	}
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		panic(fmt.Sprintf(errStrings[29], "HistogramRange()", lo, hi))
+	}
+	if !(lo < hi) {
+		panic(fmt.Sprintf(errStrings[10], "HistogramRange()", lo, hi))
+	}
+	width := (hi - lo) / float64(bins)
+	edges := make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = lo + float64(i)*width
+	}
+	edges[bins] = hi
+	counts := make([]int, bins)
+	for i := range v {
+		if !(v[i] >= lo && v[i] <= hi) {
+			continue
+		}
+		b := int((v[i] - lo) / width)
+		if b >= bins {
+			b = bins - 1
+		}
+		counts[b]++
+	}
+	return counts, edges
+}
+
+/*
+WeightedAvg returns the weighted average of a []float64, which is the sum of
+the element-wise multiplication of the []float64 and the weights, divided by
+the sum of the weights. Consider:
+
+	v := []float64{1.0, 2.0, 3.0}
+	w := []float64{1.0, 0.0, 3.0}
+	a := vec.WeightedAvg(v, w) // 2.5
+
+The passed []float64s must have the same length, and the sum of the weights
+cannot be 0.0. The passed slices are not altered in this function.
+*/
+func WeightedAvg(v, weights []float64) float64 {
+	if len(v) != len(weights) {
+		panic(fmt.Sprintf(errStrings[5], "WeightedAvg()", len(v), len(weights)))
+	}
+	total := Sum(weights)
+	if total == 0.0 {
+		panic(fmt.Sprintf(errStrings[18], "WeightedAvg()"))
+	}
+	return Dot(v, weights) / total
+}
+
+/*
+MovingAvg returns the simple moving average of a []float64 with the passed
+window size, where each element of the result is the average of window
+consecutive elements. Only the complete windows are used, so the result has
+a length of len(v)-window+1. Consider:
+
+	v := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+	a := vec.MovingAvg(v, 3) // a is {2.0, 3.0, 4.0}
+
+A running sum is used, so this function is O(len(v)) regardless of the size
+of the window. The window must be greater than 0, and cannot be larger than
+the length of the []float64. The passed []float64 is not altered in this
+function.
+*/
+func MovingAvg(v []float64, window int) []float64 {
+	if window <= 0 {
+		panic(fmt.Sprintf(errStrings[12], "MovingAvg()", "window", window))
+	}
+	if window > len(v) {
+		panic(fmt.Sprintf(errStrings[13], "MovingAvg()", "window", window, len(v)))
+	}
+	a := make([]float64, len(v)-window+1)
+	sum := Sum(v[:window])
+	a[0] = sum / float64(window)
+	for i := 1; i < len(a); i++ {
+		sum += v[i+window-1] - v[i-1]
+		a[i] = sum / float64(window)
+	}
+	return a
+}
+
+/*
+EMA returns the exponential moving average of a []float64 with the passed
+smoothing factor alpha. The first element of the result is v[0], and each
+following element is given by
+
+	ema[i] = alpha*v[i] + (1-alpha)*ema[i-1]
+
+so larger values of alpha discount older elements faster. Consider:
+
+	v := []float64{1.0, 3.0, 3.0}
+	e := vec.EMA(v, 0.5) // e is {1.0, 2.0, 2.5}
+
+The result has the same length as the passed []float64. Alpha must be in
+(0, 1], where an alpha of 1.0 returns a copy of the passed []float64. The
+passed []float64 is not altered in this function.
+*/
+func EMA(v []float64, alpha float64) []float64 {
+	if !(alpha > 0.0 && alpha <= 1.0) {
+		panic(fmt.Sprintf(errStrings[19], "EMA()", alpha))
+	}
+	e := make([]float64, len(v))
+	for i := range v {
+		if i == 0 {
+			e[i] = v[i]
+			continue
+		}
+		e[i] = alpha*v[i] + (1.0-alpha)*e[i-1]
+	}
+	return e
+}
+
+/*
+SumPairwise adds all elements in a []float64 using pairwise summation. The
+[]float64 is recursively split in half, and the sums of the halves are added
+together, until the pieces are small enough to be summed directly. Consider:
+
+	v := []float64{ 1.0, 2.0, 3.0 }
+	s := vec.SumPairwise(v) // 6.0
+
+The rounding error of pairwise summation grows with the logarithm of the
+length of the []float64, rather than linearly as in vec.Sum(), at nearly the
+same speed. The halves are also independent, which makes this approach easy
+to split across goroutines. Thus, this is the recommended way to sum large
+[]float64s. For even more accuracy at a higher cost, look at vec.SumKahan().
+
+This function does not alter the original []float64.
+*/
+func SumPairwise(v []float64) float64 {
+	if len(v) <= 128 {
+		sum := 0.0
+		for i := range v {
+			sum += v[i]
+		}
+		return sum
+	}
+	half := len(v) / 2
+	return SumPairwise(v[:half]) + SumPairwise(v[half:])
+}
+
+/*
+Zip applies a function to each pair of corresponding elements of two
+[]float64s, storing the results in a new []float64 which is returned. This
+generalizes the []float64 cases of vec.Mul(), vec.Add(), vec.Sub(), and
+vec.Div() to any element-wise binary operation. Consider:
+
+	v := []float64{1.0, 5.0, 3.0}
+	w := []float64{4.0, 2.0, 3.0}
+	z := vec.Zip(v, w, math.Max) // z is {4.0, 5.0, 3.0}
+
+The passed []float64s must have the same length, and they are not altered in
+this function.
+*/
+func Zip(v, w []float64, f func(a, b float64) float64) []float64 {
+	if len(v) != len(w) {
+		panic(fmt.Sprintf(errStrings[5], "Zip()", len(v), len(w)))
+	}
+	z := make([]float64, len(v))
+	for i := range v {
+		z[i] = f(v[i], w[i])
+	}
+	return z
+}
+
+/*
+Trapz integrates evenly spaced samples of a function using the trapezoidal
+rule, where dx is the spacing between the samples. Consider:
+
+	y := []float64{0.0, 1.0, 2.0}
+	a := vec.Trapz(y, 0.5) // 1.0
+
+A []float64 with fewer than 2 elements has an integral of 0.0. For samples
+which are not evenly spaced, look at vec.TrapzX(). The passed []float64 is
+not altered in this function.
+*/
+func Trapz(y []float64, dx float64) float64 {
+	if len(y) < 2 {
+		return 0.0
+	}
+	sum := (y[0] + y[len(y)-1]) / 2.0
+	for i := 1; i < len(y)-1; i++ {
+		sum += y[i]
+	}
+	return sum * dx
+}
+
+/*
+TrapzX integrates samples of a function using the trapezoidal rule, where
+y[i] is the value of the function at x[i]. Consider:
+
+	y := []float64{0.0, 1.0, 1.0}
+	x := []float64{0.0, 1.0, 3.0}
+	a := vec.TrapzX(y, x) // 2.5
+
+The passed []float64s must have the same length. A []float64 with fewer than
+2 elements has an integral of 0.0. The passed []float64s are not altered in
+this function.
+*/
+func TrapzX(y, x []float64) float64 {
+	if len(y) != len(x) {
+		panic(fmt.Sprintf(errStrings[5], "TrapzX()", len(y), len(x)))
+	}
+	sum := 0.0
+	for i := 1; i < len(y); i++ {
+		sum += (x[i] - x[i-1]) * (y[i] + y[i-1]) / 2.0
+	}
+	return sum
+}
+
+/*
+Diff returns the differences between successive elements of a []float64,
+such that element i of the result is v[i+1] - v[i]. Consider:
+
+	v := []float64{1.0, 4.0, 9.0, 16.0}
+	d := vec.Diff(v) // d is {3.0, 5.0, 7.0}
+
+The result is one element shorter than the passed []float64, which must
+have at least 2 elements. The passed []float64 is not altered in this
+function.
+*/
+func Diff(v []float64) []float64 {
+	if len(v) < 2 {
+		panic(fmt.Sprintf(errStrings[20], "Diff()", 2, len(v)))
+	}
+	d := make([]float64, len(v)-1)
+	for i := range d {
+		d[i] = v[i+1] - v[i]
+	}
+	return d
+}
+
+/*
+Gradient1D estimates the derivative of a function from its evenly spaced
+samples, where dx is the spacing between the samples. Central differences
+are used for the interior elements, and one-sided differences at the two
+ends, so the result has the same length as the passed []float64. Consider:
+
+	v := []float64{1.0, 4.0, 9.0, 16.0}
+	g := vec.Gradient1D(v, 1.0) // g is {3.0, 4.0, 6.0, 7.0}
+
+The passed []float64 must have at least 2 elements, and dx cannot be 0.0.
+The passed []float64 is not altered in this function.
+*/
+func Gradient1D(v []float64, dx float64) []float64 {
+	if len(v) < 2 {
+		panic(fmt.Sprintf(errStrings[20], "Gradient1D()", 2, len(v)))
+	}
+	if dx == 0.0 {
+		panic(fmt.Sprintf(errStrings[7], "Gradient1D()"))
+	}
+	g := make([]float64, len(v))
+	g[0] = (v[1] - v[0]) / dx
+	for i := 1; i < len(v)-1; i++ {
+		g[i] = (v[i+1] - v[i-1]) / (2.0 * dx)
+	}
+	g[len(v)-1] = (v[len(v)-1] - v[len(v)-2]) / dx
+	return g
+}
+
+/*
+FromFunc creates a []float64 of length n, where the element at index i is
+set to f(i). Consider:
+
+	square := func(i int) float64 {
+		return float64(i * i)
+	}
+	v := vec.FromFunc(4, square) // v is {0.0, 1.0, 4.0, 9.0}
+
+The length n cannot be negative.
+*/
+func FromFunc(n int, f func(i int) float64) []float64 {
+	if n < 0 {
+		panic(fmt.Sprintf(errStrings[21], "FromFunc()", "length", n))
+	}
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = f(i)
+	}
+	return v
+}
+
+/*
+Accumulator keeps running statistics of a stream of float64s, without
+holding on to the values themselves. Its zero value is ready to use:
+
+	var acc vec.Accumulator
+	for _, x := range []float64{2.0, 4.0, 6.0} {
+		acc.Add(x)
+	}
+	acc.Mean() // 4.0
+	acc.Var() // 4.0
+
+The mean and variance are updated using Welford's method, which is
+numerically stable even for long streams. Accumulators filled by separate
+goroutines can be combined using Merge. An Accumulator is not safe for
+concurrent use.
+*/
+type Accumulator struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+/*
+Add adds a float64 to the Accumulator, updating its statistics.
+*/
+func (a *Accumulator) Add(x float64) {
+	a.n++
+	d := x - a.mean
+	a.mean += d / float64(a.n)
+	a.m2 += d * (x - a.mean)
+}
+
+/*
+Merge adds all of the values seen by another Accumulator to this one, as if
+they had been added one at a time. The other Accumulator is not altered.
+*/
+func (a *Accumulator) Merge(other *Accumulator) {
+	if other.n == 0 {
+		return
+	}
+	if a.n == 0 {
+		*a = *other
+		return
+	}
+	n := a.n + other.n
+	d := other.mean - a.mean
+	a.mean += d * float64(other.n) / float64(n)
+	a.m2 += other.m2 + d*d*float64(a.n)*float64(other.n)/float64(n)
+	a.n = n
+}
+
+/*
+Count returns the number of values added to the Accumulator.
+*/
+func (a *Accumulator) Count() int {
+	return a.n
+}
+
+/*
+Mean returns the average of the values added to the Accumulator, or NaN if
+no values have been added.
+*/
+func (a *Accumulator) Mean() float64 {
+	if a.n == 0 {
+		return math.NaN()
+	}
+	return a.mean
+}
+
+/*
+Var returns the sample variance of the values added to the Accumulator,
+which uses N-1 in the denominator, where N is the number of values. If fewer
+than 2 values have been added, NaN is returned.
+*/
+func (a *Accumulator) Var() float64 {
+	if a.n < 2 {
+		return math.NaN()
+	}
+	return a.m2 / float64(a.n-1)
+}
+
+/*
+Std returns the sample standard deviation of the values added to the
+Accumulator, which is the square root of Var. If fewer than 2 values have
+been added, NaN is returned.
+*/
+func (a *Accumulator) Std() float64 {
+	return math.Sqrt(a.Var())
+}
+
+/*
+Axpy adds a times x to y, element by element, storing the result in y. This
+is the classic BLAS operation y += a*x. For example:
+
+	x := []float64{1.0, 2.0, 3.0}
+	y := []float64{1.0, 1.0, 1.0}
+	vec.Axpy(2.0, x, y) // y is now [3.0, 5.0, 7.0]
+
+Unlike most functions in this package, y is mutated in place, which avoids
+the intermediate allocations of vec.Add(y, vec.Mul(x, a)). The two
+[]float64s must have the same length. x is not mutated in this function.
+*/
+func Axpy(a float64, x, y []float64) {
+	if len(x) != len(y) {
+		panic(fmt.Sprintf(errStrings[5], "Axpy()", len(x), len(y)))
+	}
+	for i := range y {
+		y[i] += a * x[i]
+	}
+}
+
+/*
+TrimmedMean returns the average of a []float64 after discarding the lowest
+and the highest fraction of its elements. For example:
+
+	v := []float64{1.0, 2.0, 3.0, 4.0, 100.0}
+	m := vec.TrimmedMean(v, 0.2) // m is 3.0, the average of [2.0, 3.0, 4.0]
+
+The number of elements discarded from each end is fraction*len(v), rounded
+down. A fraction of 0.0 gives the same result as vec.Avg(v). The fraction
+must be in [0, 0.5), and the []float64 cannot be empty. The passed []float64
+is not mutated in this function.
+*/
+func TrimmedMean(v []float64, fraction float64) float64 {
+	if len(v) == 0 {
+		panic(fmt.Sprintf(errStrings[0], "TrimmedMean()", "TrimmedMean()"))
+	}
+	if !(fraction >= 0.0 && fraction < 0.5) {
+		panic(fmt.Sprintf(errStrings[22], "TrimmedMean()", fraction))
+	}
+	c := Clone(v)
+	sort.Float64s(c)
+	k := int(fraction * float64(len(c)))
+	sum := 0.0
+	for _, x := range c[k : len(c)-k] {
+		sum += x
+	}
+	return sum / float64(len(c)-2*k)
+}
+
+/*
+Rescale returns a new []float64 where each element has been mapped linearly
+from the range [oldLo, oldHi] to the range [newLo, newHi]. For example:
+
+	v := []float64{0.0, 5.0, 10.0}
+	r := vec.Rescale(v, 0.0, 10.0, -1.0, 1.0) // r is [-1.0, 0.0, 1.0]
+
+Elements outside of the old range are mapped outside of the new range; use
+vec.Clamp01 to clip the result if needed. oldLo and oldHi cannot be equal,
+but either range may be reversed. The passed []float64 is not mutated in
+this function.
+*/
+func Rescale(v []float64, oldLo, oldHi, newLo, newHi float64) []float64 {
+	if oldLo == oldHi {
+		panic(fmt.Sprintf(errStrings[23], "Rescale()", oldLo))
+	}
+	scale := (newHi - newLo) / (oldHi - oldLo)
+	r := make([]float64, len(v))
+	for i := range v {
+		r[i] = newLo + (v[i]-oldLo)*scale
+	}
+	return r
+}
+
+/*
+Clamp01 returns a new []float64 where each element of the passed []float64
+has been clipped to the range [0.0, 1.0]. For example:
+
+	v := []float64{-0.5, 0.5, 1.5}
+	c := vec.Clamp01(v) // c is [0.0, 0.5, 1.0]
+
+NaN elements are left as NaN. The passed []float64 is not mutated in this
+function.
+*/
+func Clamp01(v []float64) []float64 {
+	c := Clone(v)
+	for i := range c {
+		if c[i] < 0.0 {
+			c[i] = 0.0
+		} else if c[i] > 1.0 {
+			c[i] = 1.0
+		}
+	}
+	return c
+}
+
+/*
+Ones creates a []float64 of length n, where all of the elements are 1.0.
+The length n cannot be negative.
+*/
+func Ones(n int) []float64 {
+	if n < 0 {
+		panic(fmt.Sprintf(errStrings[21], "Ones()", "length", n))
+	}
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = 1.0
+	}
+	return v
+}
+
+/*
+Zeros creates a []float64 of length n, where all of the elements are 0.0.
+This is the same as make([]float64, n), but reads better next to vec.Ones.
+The length n cannot be negative.
+*/
+func Zeros(n int) []float64 {
+	if n < 0 {
+		panic(fmt.Sprintf(errStrings[21], "Zeros()", "length", n))
+	}
+	return make([]float64, n)
+}
+
+/*
+Inc creates a []float64 of length n, where the element at index i is set to
+float64(i). Consider:
+
+	v := vec.Inc(4) // v is {0.0, 1.0, 2.0, 3.0}
+
+The length n cannot be negative.
+*/
+func Inc(n int) []float64 {
+	if n < 0 {
+		panic(fmt.Sprintf(errStrings[21], "Inc()", "length", n))
+	}
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = float64(i)
+	}
+	return v
+}
+
+/*
+Reset sets all of the elements of a []float64 to 0.0. Unlike vec.Set, the
+passed []float64 is mutated in place, and nothing is returned.
+*/
+func Reset(v []float64) {
+	for i := range v {
+		v[i] = 0.0
+	}
+}
+
+/*
+Map applies a function to each element of a []float64, storing the result
+in a new []float64 which is returned. This is the same as vec.Foreach(v, f),
+with the arguments in the order used by the older numgo package:
+
+	v := vec.Map(math.Sqrt, []float64{1.0, 4.0, 9.0}) // v is {1.0, 2.0, 3.0}
+
+The original []float64 is not mutated in this function.
+*/
+func Map(f func(float64) float64, v []float64) []float64 {
+	return Foreach(v, f)
+}
+
+/*
+SampleIndex draws a single random index from a []float64 of weights, where
+the probability of picking index i is weights[i] divided by the sum of the
+weights. For example:
+
+	r := rand.New(rand.NewSource(42))
+	i := vec.SampleIndex([]float64{1.0, 0.0, 3.0}, r) // i is 0 or 2, and 2 is 3 times as likely
+
+The draw uses a binary search over the cumulative sum of the weights. If r
+is nil, the default source of the math/rand package is used. None of the
+weights can be negative, and their sum cannot be 0.0. The passed []float64
+is not mutated in this function.
+*/
+func SampleIndex(weights []float64, r *rand.Rand) int {
+	cum := cumWeights(weights, "SampleIndex()")
+	return searchWeights(cum, r)
+}
+
+/*
+Sample draws n random indices from a []float64 of weights, where the
+probability of picking index i is proportional to weights[i]. For example:
+
+	r := rand.New(rand.NewSource(42))
+	idx := vec.Sample([]float64{1.0, 1.0, 2.0}, 10, true, r) // 10 indices, about half of them 2
+
+If replace is true, each draw is independent, and the same index can be
+drawn many times. Otherwise, an index is never drawn twice, and each draw
+picks from the remaining indices in proportion to their weights; in this
+case n cannot be larger than the number of non-zero weights. If r is nil,
+the default source of the math/rand package is used. None of the weights
+can be negative, their sum cannot be 0.0, and n cannot be negative. The
+passed []float64 is not mutated in this function.
+*/
+func Sample(weights []float64, n int, replace bool, r *rand.Rand) []int {
+	if n < 0 {
+		panic(fmt.Sprintf(errStrings[21], "Sample()", "number of samples", n))
+	}
+	cum := cumWeights(weights, "Sample()")
+	idx := make([]int, n)
+	if replace {
+		for k := range idx {
+			idx[k] = searchWeights(cum, r)
+		}
+		return idx
+	}
+	nonZero := 0
+	for i := range weights {
+		if weights[i] > 0.0 {
+			nonZero++
+		}
+	}
+	if n > nonZero {
+		panic(fmt.Sprintf(errStrings[25], "Sample()", n, nonZero))
+	}
+	w := Clone(weights)
+	for k := range idx {
+		idx[k] = searchWeights(cum, r)
+		w[idx[k]] = 0.0
+		if k+1 < n {
+			cum = cumWeights(w, "Sample()")
+		}
+	}
+	return idx
+}
+
+// cumWeights returns the cumulative sum of the passed weights, panicking on
+// behalf of the named function if any weight is negative or the sum is 0.0.
+func cumWeights(weights []float64, name string) []float64 {
+	cum := make([]float64, len(weights))
+	total := 0.0
+	for i := range weights {
+		if weights[i] < 0.0 {
+			panic(fmt.Sprintf(errStrings[24], name, i, weights[i]))
+		}
+		total += weights[i]
+		cum[i] = total
+	}
+	if total == 0.0 {
+		panic(fmt.Sprintf(errStrings[18], name))
+	}
+	return cum
+}
+
+// searchWeights draws an index using the cumulative weights returned by
+// cumWeights. Indices with a weight of 0.0 are never returned.
+func searchWeights(cum []float64, r *rand.Rand) int {
+	var u float64
+	if r == nil {
+		u = rand.Float64()
+	} else {
+		u = r.Float64()
+	}
+	u *= cum[len(cum)-1]
+	i := sort.Search(len(cum), func(i int) bool {
+		return cum[i] > u
+	})
+	if i == len(cum) {
+		// u can round up to the total; fall back to the last non-zero weight.
+		i = len(cum) - 1
+		for i > 0 && cum[i] == cum[i-1] {
+			i--
+		}
+	}
+	return i
+}
+
+/*
+RSquared returns the coefficient of determination of a set of predictions,
+which is 1 - SS_res/SS_tot, where SS_res is the sum of the squared
+differences between the predicted and actual values, and SS_tot is the sum
+of the squared differences between the actual values and their average. For
+example:
+
+	actual := []float64{1.0, 2.0, 3.0}
+	predicted := []float64{1.0, 2.0, 4.0}
+	r2 := vec.RSquared(predicted, actual) // r2 is 0.5
+
+A perfect fit gives 1.0, and always predicting the average gives 0.0; worse
+predictions give negative values. The two []float64s must have the same
+length, and the actual values cannot all be equal. The passed []float64s
+are not mutated in this function.
+*/
+func RSquared(predicted, actual []float64) float64 {
+	if len(predicted) != len(actual) {
+		panic(fmt.Sprintf(errStrings[5], "RSquared()", len(predicted), len(actual)))
+	}
+	if len(actual) == 0 {
+		panic(fmt.Sprintf(errStrings[0], "RSquared()", "RSquared()"))
+	}
+	avg := Avg(actual)
+	res, tot := 0.0, 0.0
+	for i := range actual {
+		d := actual[i] - predicted[i]
+		res += d * d
+		d = actual[i] - avg
+		tot += d * d
+	}
+	if tot == 0.0 {
+		panic(fmt.Sprintf(errStrings[26], "RSquared()"))
+	}
+	return 1.0 - res/tot
+}
+
+/*
+Sigmoid returns a new []float64 where the logistic function,
+1 / (1 + e^-x), has been applied to each element. For example:
+
+	v := vec.Sigmoid([]float64{-1000.0, 0.0, 1000.0}) // v is [0.0, 0.5, 1.0]
+
+The function is evaluated in a form which does not overflow for large
+negative or positive inputs. The original []float64 is not mutated in this
+function.
+*/
+func Sigmoid(v []float64) []float64 {
+	return Foreach(v, sigmoid)
+}
+
+/*
+SigmoidPrime returns a new []float64 where the derivative of the logistic
+function, s * (1 - s) with s = 1 / (1 + e^-x), has been applied to each
+element. For example:
+
+	v := vec.SigmoidPrime([]float64{-1000.0, 0.0, 1000.0}) // v is [0.0, 0.25, 0.0]
+
+The logistic function is evaluated in the same overflow safe form as in
+vec.Sigmoid. The original []float64 is not mutated in this function.
+*/
+func SigmoidPrime(v []float64) []float64 {
+	return Foreach(v, func(x float64) float64 {
+		s := sigmoid(x)
+		return s * (1.0 - s)
+	})
+}
+
+// sigmoid returns the logistic function of x. For negative x it uses the
+// form e^x / (1 + e^x), so that e^-x never overflows.
+func sigmoid(x float64) float64 {
+	if x >= 0.0 {
+		return 1.0 / (1.0 + math.Exp(-x))
+	}
+	e := math.Exp(x)
+	return e / (1.0 + e)
+}
+
+/*
+Tanh returns a new []float64 where the hyperbolic tangent has been applied
+to each element. For example:
+
+	v := vec.Tanh([]float64{-1000.0, 0.0, 1000.0}) // v is [-1.0, 0.0, 1.0]
+
+The original []float64 is not mutated in this function.
+*/
+func Tanh(v []float64) []float64 {
+	return Foreach(v, math.Tanh)
+}
+
+/*
+ReLU returns a new []float64 where each negative element has been replaced
+by 0.0, which is the rectified linear unit, max(0, x). For example:
+
+	v := vec.ReLU([]float64{-2.0, 0.0, 3.0}) // v is [0.0, 0.0, 3.0]
+
+NaN elements are left as NaN. The original []float64 is not mutated in this
+function.
+*/
+func ReLU(v []float64) []float64 {
+	return Foreach(v, relu)
+}
+
+// relu returns x if it is not negative, and 0.0 otherwise.
+func relu(x float64) float64 {
+	if x < 0.0 {
+		return 0.0
+	}
+	return x
+}
+
+/*
+Exp returns a new []float64 where e^x has been applied to each element. The
+original []float64 is not mutated in this function.
+*/
+func Exp(v []float64) []float64 {
+	return Foreach(v, math.Exp)
+}
+
+/*
+Log returns a new []float64 where the natural logarithm has been applied to
+each element. Like math.Log, the logarithm of 0.0 is -Inf, and the logarithm
+of a negative number is NaN; use vec.LogStrict to panic instead. The
+original []float64 is not mutated in this function.
+*/
+func Log(v []float64) []float64 {
+	return Foreach(v, math.Log)
+}
+
+/*
+LogStrict returns a new []float64 where the natural logarithm has been
+applied to each element, just like vec.Log, but panics if any element is
+not greater than 0.0. The original []float64 is not mutated in this
+function.
+*/
+func LogStrict(v []float64) []float64 {
+	for i := range v {
+		if !(v[i] > 0.0) {
+			panic(fmt.Sprintf(errStrings[27], "LogStrict()", i, v[i], "greater than 0.0"))
+		}
+	}
+	return Foreach(v, math.Log)
+}
+
+/*
+Sqrt returns a new []float64 where the square root has been applied to each
+element. Like math.Sqrt, the square root of a negative number is NaN; use
+vec.SqrtStrict to panic instead. The original []float64 is not mutated in
+this function.
+*/
+func Sqrt(v []float64) []float64 {
+	return Foreach(v, math.Sqrt)
+}
+
+/*
+SqrtStrict returns a new []float64 where the square root has been applied
+to each element, just like vec.Sqrt, but panics if any element is negative
+or NaN. The original []float64 is not mutated in this function.
+*/
+func SqrtStrict(v []float64) []float64 {
+	for i := range v {
+		if !(v[i] >= 0.0) {
+			panic(fmt.Sprintf(errStrings[27], "SqrtStrict()", i, v[i], "at least 0.0"))
+		}
+	}
+	return Foreach(v, math.Sqrt)
+}
+
+/*
+LogClamped returns a new []float64 where each element x has been replaced
+by log(max(x, eps)). For example:
+
+	v := vec.LogClamped([]float64{0.0, 1.0}, 1e-12) // v is [log(1e-12), 0.0]
+
+This avoids the -Inf and NaN that a plain logarithm gives for zero and
+negative values, which is useful for losses such as the cross-entropy.
+NaN elements are left as NaN. eps must be greater than 0.0. The original
+[]float64 is not mutated in this function.
+*/
+func LogClamped(v []float64, eps float64) []float64 {
+	if !(eps > 0.0) {
+		panic(fmt.Sprintf(errStrings[28], "LogClamped()", eps))
+	}
+	c := Clone(v)
+	for i := range c {
+		if c[i] < eps {
+			c[i] = eps
+		}
+		c[i] = math.Log(c[i])
+	}
+	return c
+}
@@ -2,6 +2,8 @@ package vec
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"sync"
 	"testing"
 )
@@ -199,6 +201,42 @@ func TestRand(t *testing.T) {
 			t.Errorf("expected value in [0.0, 1.0] but got %f", v[i])
 		}
 	}
+	v = Rand(100, -5.0)
+	for i := range v {
+		if v[i] <= -5.0 || v[i] > 0.0 {
+			t.Errorf("expected value in (-5.0, 0.0] but got %f", v[i])
+		}
+	}
+	v = Rand(100, -12.0, 2.0)
+	if len(v) != 100 {
+		t.Errorf("expected length of 100, but got %d", len(v))
+	}
+	zeros := 0
+	for i := range v {
+		if v[i] >= 2.0 || v[i] < -12.0 {
+			t.Errorf("expected value in [-12.0, 2.0) but got %f", v[i])
+		}
+		if v[i] == 0.0 {
+			zeros++
+		}
+	}
+	if zeros == len(v) {
+		t.Errorf("expected the []float64 to be filled, but all values are 0.0")
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[10], "Rand()", 2.0, -12.0)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Rand(10, 2.0, -12.0)
+	}()
+	wg.Wait()
 }
 
 func TestClone(t *testing.T) {
@@ -451,3 +489,992 @@ func TestDot(t *testing.T) {
 		t.Errorf("expected result to be %f, but got %f", 13.0*3.0, res)
 	}
 }
+
+func TestUnique(t *testing.T) {
+	v := []float64{3.0, 1.0, 3.0, 2.0, 1.0}
+	u := Unique(v)
+	if !Equal(u, []float64{3.0, 1.0, 2.0}) {
+		t.Errorf("expected [3.0, 1.0, 2.0], got %v", u)
+	}
+	if len(v) != 5 {
+		t.Errorf("expected the original to be intact, got %v", v)
+	}
+	u = Unique([]float64{math.NaN(), math.NaN()})
+	if len(u) != 2 {
+		t.Errorf("expected NaNs to be kept distinct, got %v", u)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	v := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+	w := Windows(v, 3, 1)
+	if len(w) != 3 {
+		t.Errorf("expected 3 windows, got %d", len(w))
+	}
+	if !Equal(w[2], []float64{3.0, 4.0, 5.0}) {
+		t.Errorf("expected [3.0, 4.0, 5.0], got %v", w[2])
+	}
+	w = Windows(v, 2, 2)
+	if len(w) != 2 {
+		t.Errorf("expected 2 windows, got %d", len(w))
+	}
+	if !Equal(w[1], []float64{3.0, 4.0}) {
+		t.Errorf("expected [3.0, 4.0], got %v", w[1])
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[13], "Windows()", "size", 6, len(v))
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		w = Windows(v, 6, 1)
+	}()
+	wg.Wait()
+}
+
+func TestConvolve(t *testing.T) {
+	v := []float64{1.0, 2.0, 3.0}
+	k := []float64{0.0, 1.0, 0.5}
+	c := Convolve(v, k, "full")
+	if !Equal(c, []float64{0.0, 1.0, 2.5, 4.0, 1.5}) {
+		t.Errorf("expected [0.0, 1.0, 2.5, 4.0, 1.5], got %v", c)
+	}
+	c = Convolve(v, k, "same")
+	if !Equal(c, []float64{1.0, 2.5, 4.0}) {
+		t.Errorf("expected [1.0, 2.5, 4.0], got %v", c)
+	}
+	c = Convolve(v, k, "valid")
+	if !Equal(c, []float64{2.5}) {
+		t.Errorf("expected [2.5], got %v", c)
+	}
+	v = []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+	k = []float64{1.0, 1.0, 1.0, 1.0}
+	c = Convolve(v, k, "same")
+	if !Equal(c, []float64{3.0, 6.0, 10.0, 14.0, 12.0}) {
+		t.Errorf("expected [3.0, 6.0, 10.0, 14.0, 12.0], got %v", c)
+	}
+	c = Convolve(k, v, "valid")
+	if !Equal(c, []float64{10.0, 14.0}) {
+		t.Errorf("expected [10.0, 14.0], got %v", c)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[14], "Convolve()", "bad", "\"full\", \"same\", or \"valid\"")
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		c = Convolve(v, k, "bad")
+	}()
+	wg.Wait()
+}
+
+func TestSumKahan(t *testing.T) {
+	n := 1000000
+	v := make([]float64, n+1)
+	v = Set(v, 1e-16)
+	v[0] = 1.0
+	expected := 1.0 + float64(n)*1e-16
+	naive := math.Abs(Sum(v) - expected)
+	kahan := math.Abs(SumKahan(v) - expected)
+	if kahan >= naive {
+		t.Errorf("expected SumKahan error %e to be less than Sum error %e", kahan, naive)
+	}
+	if kahan > 1e-15 {
+		t.Errorf("expected %.17f, got %.17f", expected, SumKahan(v))
+	}
+}
+
+func TestDotKahan(t *testing.T) {
+	n := 1000000
+	v1 := make([]float64, n+1)
+	v1 = Set(v1, 1e-8)
+	v1[0] = 1.0
+	expected := 1.0 + float64(n)*1e-16
+	naive := math.Abs(Dot(v1, v1) - expected)
+	kahan := math.Abs(DotKahan(v1, v1) - expected)
+	if kahan >= naive {
+		t.Errorf("expected DotKahan error %e to be less than Dot error %e", kahan, naive)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	max := func(acc, val float64) float64 {
+		return math.Max(acc, val)
+	}
+	v := []float64{1.0, 3.0, 2.0}
+	res := Reduce(v, math.Inf(-1), max)
+	if res != 3.0 {
+		t.Errorf("expected 3.0, got %f", res)
+	}
+	mul := func(acc, val float64) float64 {
+		return acc * val
+	}
+	res = Reduce(v, 1.0, mul)
+	if res != Prod(v) {
+		t.Errorf("expected %f, got %f", Prod(v), res)
+	}
+}
+
+func TestNorm(t *testing.T) {
+	v := []float64{3.0, 4.0}
+	n := Norm(v)
+	if n != 5.0 {
+		t.Errorf("expected 5.0, got %f", n)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := []float64{3.0, 4.0}
+	u := Normalize(v)
+	if !Equal(u, []float64{0.6, 0.8}) {
+		t.Errorf("expected [0.6, 0.8], got %v", u)
+	}
+	if !Equal(v, []float64{3.0, 4.0}) {
+		t.Errorf("expected the original to be intact, got %v", v)
+	}
+	NormalizeInPlace(v)
+	if !Equal(v, u) {
+		t.Errorf("expected %v, got %v", u, v)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[15], "Normalize()")
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		u = Normalize(make([]float64, 3))
+	}()
+	wg.Wait()
+}
+
+func TestSoftmax(t *testing.T) {
+	v := []float64{1.0, 1.0}
+	s := Softmax(v)
+	if !Equal(s, []float64{0.5, 0.5}) {
+		t.Errorf("expected [0.5, 0.5], got %v", s)
+	}
+	v = []float64{1000.0, 0.0, -1000.0}
+	s = Softmax(v)
+	if s[0] != 1.0 || s[1] != 0.0 || s[2] != 0.0 {
+		t.Errorf("expected [1.0, 0.0, 0.0], got %v", s)
+	}
+	v = []float64{1.0, 2.0, 3.0}
+	s = Softmax(v)
+	if math.Abs(Sum(s)-1.0) > 1e-15 {
+		t.Errorf("expected the sum to be 1.0, got %f", Sum(s))
+	}
+}
+
+func TestCosineSim(t *testing.T) {
+	v := []float64{1.0, 0.0}
+	w := []float64{0.0, 2.0}
+	if c := CosineSim(v, w); c != 0.0 {
+		t.Errorf("expected 0.0, got %f", c)
+	}
+	w = []float64{3.0, 0.0}
+	if c := CosineSim(v, w); c != 1.0 {
+		t.Errorf("expected 1.0, got %f", c)
+	}
+	w = []float64{-3.0, 0.0}
+	if c := CosineSim(v, w); c != -1.0 {
+		t.Errorf("expected -1.0, got %f", c)
+	}
+}
+
+func TestDist(t *testing.T) {
+	v := []float64{0.0, 0.0}
+	w := []float64{3.0, 4.0}
+	if d := Dist(v, w); d != 5.0 {
+		t.Errorf("expected 5.0, got %f", d)
+	}
+}
+
+func TestDistManhattan(t *testing.T) {
+	v := []float64{0.0, 0.0}
+	w := []float64{3.0, -4.0}
+	if d := DistManhattan(v, w); d != 7.0 {
+		t.Errorf("expected 7.0, got %f", d)
+	}
+}
+
+func TestDistMinkowski(t *testing.T) {
+	v := []float64{0.0, 0.0}
+	w := []float64{3.0, -4.0}
+	if d := DistMinkowski(v, w, 1.0); d != 7.0 {
+		t.Errorf("expected 7.0, got %f", d)
+	}
+	if d := DistMinkowski(v, w, 2.0); d != 5.0 {
+		t.Errorf("expected 5.0, got %f", d)
+	}
+	if d := DistMinkowski(v, w, math.Inf(1)); d != 4.0 {
+		t.Errorf("expected 4.0, got %f", d)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[16], "DistMinkowski()", 0.5)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		DistMinkowski(v, w, 0.5)
+	}()
+	wg.Wait()
+}
+
+func TestGradient(t *testing.T) {
+	f := func(x []float64) float64 {
+		return x[0]*x[0] + 3.0*x[1]
+	}
+	x := []float64{1.0, 1.0}
+	g := Gradient(f, x, 0.0)
+	if math.Abs(g[0]-2.0) > 1e-8 || math.Abs(g[1]-3.0) > 1e-8 {
+		t.Errorf("expected [2.0, 3.0], got %v", g)
+	}
+	if !Equal(x, []float64{1.0, 1.0}) {
+		t.Errorf("expected x to be intact, got %v", x)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	v := []float64{1.0, 2.0, 2.0, 3.0, 5.0}
+	counts, edges := Histogram(v, 2)
+	if len(counts) != 2 || counts[0] != 3 || counts[1] != 2 {
+		t.Errorf("expected [3, 2], got %v", counts)
+	}
+	if !Equal(edges, []float64{1.0, 3.0, 5.0}) {
+		t.Errorf("expected [1.0, 3.0, 5.0], got %v", edges)
+	}
+	counts, edges = Histogram([]float64{4.0, 4.0}, 1)
+	if counts[0] != 2 {
+		t.Errorf("expected [2], got %v", counts)
+	}
+	if !Equal(edges, []float64{3.5, 4.5}) {
+		t.Errorf("expected [3.5, 4.5], got %v", edges)
+	}
+	counts, edges = Histogram([]float64{1.0, math.NaN(), 3.0}, 2)
+	if len(counts) != 2 || counts[0] != 1 || counts[1] != 1 {
+		t.Errorf("expected [1, 1], got %v", counts)
+	}
+	if !Equal(edges, []float64{1.0, 2.0, 3.0}) {
+		t.Errorf("expected [1.0, 2.0, 3.0], got %v", edges)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[0], "Histogram()", "Histogram()")
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Histogram([]float64{math.NaN(), math.NaN()}, 2)
+	}()
+	wg.Wait()
+	counts, edges = Histogram([]float64{1.0, 2.0, math.Inf(1), 3.0, math.Inf(-1)}, 2)
+	if len(counts) != 2 || counts[0] != 1 || counts[1] != 2 {
+		t.Errorf("expected [1, 2], got %v", counts)
+	}
+	if !Equal(edges, []float64{1.0, 2.0, 3.0}) {
+		t.Errorf("expected [1.0, 2.0, 3.0], got %v", edges)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[0], "Histogram()", "Histogram()")
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Histogram([]float64{math.Inf(1), math.NaN()}, 2)
+	}()
+	wg.Wait()
+}
+
+func TestHistogramRange(t *testing.T) {
+	v := []float64{-1.0, 0.5, 1.5, 2.0, 7.0}
+	counts, edges := HistogramRange(v, 2, 0.0, 2.0)
+	if len(counts) != 2 || counts[0] != 1 || counts[1] != 2 {
+		t.Errorf("expected [1, 2], got %v", counts)
+	}
+	if !Equal(edges, []float64{0.0, 1.0, 2.0}) {
+		t.Errorf("expected [0.0, 1.0, 2.0], got %v", edges)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[12], "HistogramRange()", "number of bins", 0)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		HistogramRange(v, 0, 0.0, 2.0)
+	}()
+	wg.Wait()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[29], "HistogramRange()", 0.0, math.Inf(1))
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		HistogramRange(v, 2, 0.0, math.Inf(1))
+	}()
+	wg.Wait()
+}
+
+func TestWeightedAvg(t *testing.T) {
+	v := []float64{1.0, 2.0, 3.0}
+	w := []float64{1.0, 0.0, 3.0}
+	if a := WeightedAvg(v, w); a != 2.5 {
+		t.Errorf("expected 2.5, got %f", a)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[18], "WeightedAvg()")
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		WeightedAvg(v, []float64{1.0, 0.0, -1.0})
+	}()
+	wg.Wait()
+}
+
+func TestMovingAvg(t *testing.T) {
+	v := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
+	a := MovingAvg(v, 3)
+	if !Equal(a, []float64{2.0, 3.0, 4.0}) {
+		t.Errorf("expected [2.0, 3.0, 4.0], got %v", a)
+	}
+	a = MovingAvg(v, 5)
+	if !Equal(a, []float64{3.0}) {
+		t.Errorf("expected [3.0], got %v", a)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[12], "MovingAvg()", "window", 0)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		MovingAvg(v, 0)
+	}()
+	wg.Wait()
+}
+
+func TestEMA(t *testing.T) {
+	v := []float64{1.0, 3.0, 3.0}
+	e := EMA(v, 0.5)
+	if !Equal(e, []float64{1.0, 2.0, 2.5}) {
+		t.Errorf("expected [1.0, 2.0, 2.5], got %v", e)
+	}
+	e = EMA(v, 1.0)
+	if !Equal(e, v) {
+		t.Errorf("expected %v, got %v", v, e)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[19], "EMA()", 0.0)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		EMA(v, 0.0)
+	}()
+	wg.Wait()
+}
+
+func TestSumPairwise(t *testing.T) {
+	v := []float64{1.0, 2.0, 3.0}
+	if s := SumPairwise(v); s != 6.0 {
+		t.Errorf("expected 6.0, got %f", s)
+	}
+	v = make([]float64, 1000000)
+	v = Set(v, 0.1)
+	// The exact sum of 1e6 copies of float64(0.1) is 100000.0000000000055...,
+	// which rounds to 100000.0.
+	expected := 100000.0
+	naive := math.Abs(Sum(v) - expected)
+	pairwise := math.Abs(SumPairwise(v) - expected)
+	if pairwise >= naive {
+		t.Errorf("expected SumPairwise error %e to be less than Sum error %e", pairwise, naive)
+	}
+	if pairwise > 1e-9 {
+		t.Errorf("expected %.12f, got %.12f", expected, SumPairwise(v))
+	}
+}
+
+func TestZip(t *testing.T) {
+	v := []float64{1.0, 5.0, 3.0}
+	w := []float64{4.0, 2.0, 3.0}
+	z := Zip(v, w, math.Max)
+	if !Equal(z, []float64{4.0, 5.0, 3.0}) {
+		t.Errorf("expected [4.0, 5.0, 3.0], got %v", z)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[5], "Zip()", 3, 2)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		Zip(v, w[:2], math.Max)
+	}()
+	wg.Wait()
+}
+
+func TestTrapz(t *testing.T) {
+	y := []float64{0.0, 1.0, 2.0}
+	if a := Trapz(y, 0.5); a != 1.0 {
+		t.Errorf("expected 1.0, got %f", a)
+	}
+	if a := Trapz(y[:1], 0.5); a != 0.0 {
+		t.Errorf("expected 0.0, got %f", a)
+	}
+}
+
+func TestTrapzX(t *testing.T) {
+	y := []float64{0.0, 1.0, 1.0}
+	x := []float64{0.0, 1.0, 3.0}
+	if a := TrapzX(y, x); a != 2.5 {
+		t.Errorf("expected 2.5, got %f", a)
+	}
+	x = []float64{0.0, 0.5, 1.0}
+	if a := TrapzX(y, x); a != Trapz(y, 0.5) {
+		t.Errorf("expected %f, got %f", Trapz(y, 0.5), a)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	v := []float64{1.0, 4.0, 9.0, 16.0}
+	d := Diff(v)
+	if !Equal(d, []float64{3.0, 5.0, 7.0}) {
+		t.Errorf("expected [3.0, 5.0, 7.0], got %v", d)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[20], "Diff()", 2, 1)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		Diff(v[:1])
+	}()
+	wg.Wait()
+}
+
+func TestGradient1D(t *testing.T) {
+	v := []float64{1.0, 4.0, 9.0, 16.0}
+	g := Gradient1D(v, 1.0)
+	if !Equal(g, []float64{3.0, 4.0, 6.0, 7.0}) {
+		t.Errorf("expected [3.0, 4.0, 6.0, 7.0], got %v", g)
+	}
+	g = Gradient1D(v, 0.5)
+	if !Equal(g, []float64{6.0, 8.0, 12.0, 14.0}) {
+		t.Errorf("expected [6.0, 8.0, 12.0, 14.0], got %v", g)
+	}
+}
+
+func TestFromFunc(t *testing.T) {
+	square := func(i int) float64 {
+		return float64(i * i)
+	}
+	v := FromFunc(4, square)
+	if !Equal(v, []float64{0.0, 1.0, 4.0, 9.0}) {
+		t.Errorf("expected [0.0, 1.0, 4.0, 9.0], got %v", v)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer func() {
+			r := recover()
+			expectedErr := fmt.Sprintf(errStrings[21], "FromFunc()", "length", -1)
+			if r != expectedErr {
+				t.Errorf("expected %s, got %v", expectedErr, r)
+			}
+			wg.Done()
+		}()
+		FromFunc(-1, square)
+	}()
+	wg.Wait()
+}
+
+func TestAccumulator(t *testing.T) {
+	var acc Accumulator
+	if !math.IsNaN(acc.Mean()) {
+		t.Errorf("expected the mean of nothing to be NaN, got %f", acc.Mean())
+	}
+	for _, x := range []float64{2.0, 4.0, 6.0} {
+		acc.Add(x)
+	}
+	if acc.Count() != 3 {
+		t.Errorf("expected count of 3, got %d", acc.Count())
+	}
+	if acc.Mean() != 4.0 {
+		t.Errorf("expected mean of 4.0, got %f", acc.Mean())
+	}
+	if acc.Var() != 4.0 {
+		t.Errorf("expected variance of 4.0, got %f", acc.Var())
+	}
+	if acc.Std() != 2.0 {
+		t.Errorf("expected standard deviation of 2.0, got %f", acc.Std())
+	}
+	var a, b, all Accumulator
+	v := FromFunc(100, func(i int) float64 {
+		return math.Sin(float64(i)) * 10.0
+	})
+	for i := range v {
+		if i < 37 {
+			a.Add(v[i])
+		} else {
+			b.Add(v[i])
+		}
+		all.Add(v[i])
+	}
+	a.Merge(&b)
+	if a.Count() != all.Count() {
+		t.Errorf("expected count of %d, got %d", all.Count(), a.Count())
+	}
+	if math.Abs(a.Mean()-all.Mean()) > 1e-12 {
+		t.Errorf("expected mean of %f, got %f", all.Mean(), a.Mean())
+	}
+	if math.Abs(a.Var()-all.Var()) > 1e-10 {
+		t.Errorf("expected variance of %f, got %f", all.Var(), a.Var())
+	}
+}
+
+func TestAxpy(t *testing.T) {
+	x := []float64{1.0, 2.0, 3.0}
+	y := []float64{1.0, 1.0, 1.0}
+	Axpy(2.0, x, y)
+	if !Equal(y, []float64{3.0, 5.0, 7.0}) {
+		t.Errorf("expected [3.0, 5.0, 7.0], got %v", y)
+	}
+	if !Equal(x, []float64{1.0, 2.0, 3.0}) {
+		t.Errorf("x was mutated: %v", x)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[5], "Axpy()", 3, 2)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Axpy(1.0, x, []float64{1.0, 2.0})
+	}()
+	wg.Wait()
+}
+
+func TestTrimmedMean(t *testing.T) {
+	v := []float64{100.0, 2.0, 4.0, 1.0, 3.0}
+	if m := TrimmedMean(v, 0.2); m != 3.0 {
+		t.Errorf("expected 3.0, got %f", m)
+	}
+	if m := TrimmedMean(v, 0.0); m != 22.0 {
+		t.Errorf("expected 22.0, got %f", m)
+	}
+	if m := TrimmedMean(v, 0.49); m != 3.0 {
+		t.Errorf("expected 3.0, got %f", m)
+	}
+	if v[0] != 100.0 {
+		t.Errorf("the []float64 was mutated: %v", v)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[22], "TrimmedMean()", 0.5)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		TrimmedMean(v, 0.5)
+	}()
+	wg.Wait()
+}
+
+func TestRescale(t *testing.T) {
+	v := []float64{0.0, 5.0, 10.0, 20.0}
+	r := Rescale(v, 0.0, 10.0, -1.0, 1.0)
+	if !Equal(r, []float64{-1.0, 0.0, 1.0, 3.0}) {
+		t.Errorf("expected [-1.0, 0.0, 1.0, 3.0], got %v", r)
+	}
+	r = Rescale(v, 10.0, 0.0, 0.0, 1.0)
+	if !Equal(r, []float64{1.0, 0.5, 0.0, -1.0}) {
+		t.Errorf("expected [1.0, 0.5, 0.0, -1.0], got %v", r)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[23], "Rescale()", 2.0)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Rescale(v, 2.0, 2.0, 0.0, 1.0)
+	}()
+	wg.Wait()
+}
+
+func TestClamp01(t *testing.T) {
+	v := []float64{-0.5, 0.0, 0.5, 1.0, 1.5}
+	c := Clamp01(v)
+	if !Equal(c, []float64{0.0, 0.0, 0.5, 1.0, 1.0}) {
+		t.Errorf("expected [0.0, 0.0, 0.5, 1.0, 1.0], got %v", c)
+	}
+	if v[0] != -0.5 {
+		t.Errorf("the []float64 was mutated: %v", v)
+	}
+	if c := Clamp01([]float64{math.NaN()}); !math.IsNaN(c[0]) {
+		t.Errorf("expected NaN to be kept, got %f", c[0])
+	}
+}
+
+func TestOnes(t *testing.T) {
+	v := Ones(5)
+	if !Equal(v, []float64{1.0, 1.0, 1.0, 1.0, 1.0}) {
+		t.Errorf("expected five 1.0s, got %v", v)
+	}
+	if len(Ones(0)) != 0 {
+		t.Errorf("expected an empty []float64")
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[21], "Ones()", "length", -1)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Ones(-1)
+	}()
+	wg.Wait()
+}
+
+func TestZeros(t *testing.T) {
+	v := Zeros(3)
+	if !Equal(v, []float64{0.0, 0.0, 0.0}) {
+		t.Errorf("expected three 0.0s, got %v", v)
+	}
+}
+
+func TestInc(t *testing.T) {
+	v := Inc(4)
+	if !Equal(v, []float64{0.0, 1.0, 2.0, 3.0}) {
+		t.Errorf("expected [0.0, 1.0, 2.0, 3.0], got %v", v)
+	}
+}
+
+func TestReset(t *testing.T) {
+	v := []float64{1.0, -2.0, 3.0}
+	Reset(v)
+	if !Equal(v, []float64{0.0, 0.0, 0.0}) {
+		t.Errorf("expected three 0.0s, got %v", v)
+	}
+}
+
+func TestMap(t *testing.T) {
+	v := []float64{1.0, 4.0, 9.0}
+	w := Map(math.Sqrt, v)
+	if !Equal(w, []float64{1.0, 2.0, 3.0}) {
+		t.Errorf("expected [1.0, 2.0, 3.0], got %v", w)
+	}
+	if v[1] != 4.0 {
+		t.Errorf("the []float64 was mutated: %v", v)
+	}
+}
+
+func TestSampleIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	w := []float64{1.0, 0.0, 3.0}
+	counts := make([]int, len(w))
+	for i := 0; i < 4000; i++ {
+		counts[SampleIndex(w, r)]++
+	}
+	if counts[1] != 0 {
+		t.Errorf("expected index 1 to never be drawn, got %d draws", counts[1])
+	}
+	if counts[2] < 2700 || counts[2] > 3300 {
+		t.Errorf("expected about 3000 draws of index 2, got %d", counts[2])
+	}
+	if i := SampleIndex(w, nil); i != 0 && i != 2 {
+		t.Errorf("expected 0 or 2 with the default source, got %d", i)
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[24], "SampleIndex()", 1, -1.0)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		SampleIndex([]float64{1.0, -1.0}, r)
+	}()
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[18], "SampleIndex()")
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		SampleIndex([]float64{0.0, 0.0}, nil)
+	}()
+	wg.Wait()
+}
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	w := []float64{1.0, 1.0, 0.0, 2.0}
+	idx := Sample(w, 100, true, r)
+	if len(idx) != 100 {
+		t.Errorf("expected 100 indices, got %d", len(idx))
+	}
+	for _, i := range idx {
+		if i == 2 || i < 0 || i >= len(w) {
+			t.Errorf("unexpected index %d", i)
+		}
+	}
+	idx = Sample(w, 3, false, r)
+	seen := make(map[int]bool)
+	for _, i := range idx {
+		if seen[i] || i == 2 {
+			t.Errorf("unexpected index %d in %v", i, idx)
+		}
+		seen[i] = true
+	}
+	if w[0] != 1.0 || w[3] != 2.0 {
+		t.Errorf("the weights were mutated: %v", w)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[25], "Sample()", 4, 3)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		Sample(w, 4, false, r)
+	}()
+	wg.Wait()
+}
+
+func TestRSquared(t *testing.T) {
+	actual := []float64{1.0, 2.0, 3.0}
+	if r2 := RSquared([]float64{1.0, 2.0, 4.0}, actual); r2 != 0.5 {
+		t.Errorf("expected 0.5, got %f", r2)
+	}
+	if r2 := RSquared(actual, actual); r2 != 1.0 {
+		t.Errorf("expected 1.0, got %f", r2)
+	}
+	if r2 := RSquared([]float64{2.0, 2.0, 2.0}, actual); r2 != 0.0 {
+		t.Errorf("expected 0.0, got %f", r2)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[26], "RSquared()")
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		RSquared(actual, []float64{2.0, 2.0, 2.0})
+	}()
+	wg.Wait()
+}
+
+func TestSigmoid(t *testing.T) {
+	v := []float64{-1000.0, -1.0, 0.0, 1.0, 1000.0}
+	s := Sigmoid(v)
+	e := []float64{0.0, 1.0 / (1.0 + math.E), 0.5, 1.0 / (1.0 + 1.0/math.E), 1.0}
+	for i := range e {
+		if math.Abs(s[i]-e[i]) > 1e-15 || math.IsNaN(s[i]) {
+			t.Errorf("at index %d, expected %v, got %v", i, e[i], s[i])
+		}
+	}
+	if v[0] != -1000.0 {
+		t.Errorf("the []float64 was mutated: %v", v)
+	}
+}
+
+func TestSigmoidPrime(t *testing.T) {
+	v := []float64{-1000.0, -1.0, 0.0, 2.0, 1000.0}
+	d := SigmoidPrime(v)
+	for i := range v {
+		s := 1.0 / (1.0 + math.Exp(-v[i]))
+		e := s * (1.0 - s)
+		if math.Abs(d[i]-e) > 1e-15 || math.IsNaN(d[i]) {
+			t.Errorf("at index %d, expected %v, got %v", i, e, d[i])
+		}
+	}
+	if d[2] != 0.25 {
+		t.Errorf("expected 0.25 at 0.0, got %v", d[2])
+	}
+	if v[0] != -1000.0 {
+		t.Errorf("the []float64 was mutated: %v", v)
+	}
+}
+
+func TestTanh(t *testing.T) {
+	v := Tanh([]float64{-1000.0, 0.0, 0.5, 1000.0})
+	e := []float64{-1.0, 0.0, math.Tanh(0.5), 1.0}
+	if !Equal(v, e) {
+		t.Errorf("expected %v, got %v", e, v)
+	}
+}
+
+func TestReLU(t *testing.T) {
+	w := []float64{-2.0, 0.0, 3.0, math.NaN()}
+	v := ReLU(w)
+	if !Equal(v[:3], []float64{0.0, 0.0, 3.0}) || !math.IsNaN(v[3]) {
+		t.Errorf("expected [0.0, 0.0, 3.0, NaN], got %v", v)
+	}
+	if w[0] != -2.0 {
+		t.Errorf("the []float64 was mutated: %v", w)
+	}
+}
+
+func TestExp(t *testing.T) {
+	v := Exp([]float64{0.0, 1.0})
+	if !Equal(v, []float64{1.0, math.E}) {
+		t.Errorf("expected [1.0, e], got %v", v)
+	}
+}
+
+func TestLog(t *testing.T) {
+	v := Log([]float64{1.0, math.E, 0.0, -1.0})
+	if v[0] != 0.0 || v[1] != 1.0 || !math.IsInf(v[2], -1) || !math.IsNaN(v[3]) {
+		t.Errorf("expected [0.0, 1.0, -Inf, NaN], got %v", v)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[27], "LogStrict()", 1, 0.0, "greater than 0.0")
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		LogStrict([]float64{1.0, 0.0})
+	}()
+	wg.Wait()
+}
+
+func TestSqrt(t *testing.T) {
+	v := Sqrt([]float64{4.0, 0.0, -1.0})
+	if v[0] != 2.0 || v[1] != 0.0 || !math.IsNaN(v[2]) {
+		t.Errorf("expected [2.0, 0.0, NaN], got %v", v)
+	}
+	if w := SqrtStrict([]float64{9.0, 0.0}); !Equal(w, []float64{3.0, 0.0}) {
+		t.Errorf("expected [3.0, 0.0], got %v", w)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[27], "SqrtStrict()", 0, -1.0, "at least 0.0")
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		SqrtStrict([]float64{-1.0})
+	}()
+	wg.Wait()
+}
+
+func TestLogClamped(t *testing.T) {
+	v := LogClamped([]float64{0.0, -3.0, 1.0, math.E, math.NaN()}, 1e-12)
+	e := math.Log(1e-12)
+	if v[0] != e || v[1] != e || v[2] != 0.0 || v[3] != 1.0 || !math.IsNaN(v[4]) {
+		t.Errorf("expected [%f, %f, 0.0, 1.0, NaN], got %v", e, e, v)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			r := recover()
+			e := fmt.Sprintf(errStrings[28], "LogClamped()", 0.0)
+			if r != e {
+				t.Errorf("expected panic %q, got %v", e, r)
+			}
+		}()
+		LogClamped([]float64{1.0}, 0.0)
+	}()
+	wg.Wait()
+}